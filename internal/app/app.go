@@ -1,23 +1,68 @@
 package app
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"os/exec"
+	"net/http"
 	"path/filepath"
 )
 
 const (
-	cursorURL  = "https://downloader.cursor.sh/linux/appImage/x64"
-	appImage   = "Cursor.AppImage"
-	installDir = "/opt/cursor"
+	cursorURL = "https://downloader.cursor.sh/linux/appImage/x64"
+	appImage  = "Cursor.AppImage"
+	iconFile  = "cursor.png"
 )
 
+var (
+	installDir       = "/opt/cursor"
+	desktopEntryPath = "/usr/share/applications/cursor.desktop"
+	symlinkPath      = "/usr/local/bin/cursor"
+	metadataPath     = "/opt/cursor/metadata.json"
+	cacheDirOverride string
+)
+
+type Paths struct {
+	InstallDir   string
+	Symlink      string
+	DesktopEntry string
+	CacheDir     string
+}
+
+func SetPaths(paths Paths) {
+	if paths.InstallDir != "" {
+		installDir = filepath.Clean(paths.InstallDir)
+		metadataPath = filepath.Join(installDir, "metadata.json")
+	}
+	if paths.Symlink != "" {
+		symlinkPath = paths.Symlink
+	}
+	if paths.DesktopEntry != "" {
+		desktopEntryPath = paths.DesktopEntry
+	}
+	cacheDirOverride = paths.CacheDir
+}
+
 type Installer struct {
 	downloadOnly      bool
 	forceInstall      bool
 	configureSettings bool
 	version           string
+	user              *TargetUser
+	runningPolicy     RunningPolicy
+	staging           bool
+	layout            string
+	mirror            string
+	httpOptions       HTTPOptions
+	client            *http.Client
+	checksum          string
+	cacheRetention    int
+	hooks             map[HookPoint]string
+	previousVersion   string
+	downloadPath      string
+	report            *Report
+	changelogSource   string
+	targetVersion     string
+	pruneExtensions   bool
 }
 
 type InstallationStatus struct {
@@ -31,31 +76,36 @@ func NewInstaller(downloadOnly, forceInstall bool, configureSettings bool) *Inst
 		downloadOnly:      downloadOnly,
 		forceInstall:      forceInstall,
 		configureSettings: configureSettings,
+		report:            &Report{},
 	}
 }
 
-func (i *Installer) CheckSudoAccess() error {
-	cmd := exec.Command("sudo", "-n", "true")
-	cmd.Stderr = os.Stderr
+func (i *Installer) CheckSudoAccess(ctx context.Context) error {
+	cmd := commandContext(ctx, "sudo", "-n", "true")
+	cmd.Stderr = stderr(ctx)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("this installer requires sudo privileges. Please ensure you have sudo access and try again. You can run `sudo usermod -aG sudo <user>` to add your user to the sudoers group")
 	}
 	return nil
 }
 
-func (i *Installer) CheckInstallation() *InstallationStatus {
-	metadata, err := i.readMetadata()
+func (i *Installer) CheckInstallation(ctx context.Context) *InstallationStatus {
+	metadata, err := i.readMetadata(ctx)
 	if err != nil {
 		return &InstallationStatus{Error: fmt.Errorf("failed to read installation metadata: %v", err)}
 	}
-
-	_, err = os.Stat(filepath.Join(installDir, appImage))
-	if os.IsNotExist(err) {
-		return &InstallationStatus{}
+	i.applyMetadataLayout(metadata)
+	if metadata != nil {
+		i.previousVersion = metadata.CurrentVersion()
 	}
+
+	installed, err := isInstalled()
 	if err != nil {
 		return &InstallationStatus{Error: fmt.Errorf("failed to check installation: %v", err)}
 	}
+	if !installed {
+		return &InstallationStatus{}
+	}
 
 	if i.forceInstall {
 		return &InstallationStatus{}
@@ -64,7 +114,7 @@ func (i *Installer) CheckInstallation() *InstallationStatus {
 	if metadata != nil {
 		return &InstallationStatus{
 			AlreadyUpToDate: false,
-			CurrentVersion:  metadata.Version,
+			CurrentVersion:  metadata.CurrentVersion(),
 		}
 	}
 
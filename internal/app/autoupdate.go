@@ -0,0 +1,312 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	autoUpdateUnit      = "cursor-installer-update"
+	systemUnitDir       = "/etc/systemd/system"
+	systemAutoUpdateLog = "/var/log/cursor-installer/auto-update.log"
+	systemAutoUpdateExe = "/usr/local/libexec/cursor-installer/cursor-installer"
+)
+
+type AutoUpdateScope string
+
+const (
+	AutoUpdateUser   AutoUpdateScope = "user"
+	AutoUpdateSystem AutoUpdateScope = "system"
+)
+
+type AutoUpdateStatus struct {
+	Scope     AutoUpdateScope
+	Installed bool
+	Enabled   string
+	Active    string
+	Schedule  string
+	NextRun   string
+	LastRun   string
+	LogPath   string
+	RecentLog []string
+}
+
+func ParseAutoUpdateScope(scope string) (AutoUpdateScope, error) {
+	switch AutoUpdateScope(scope) {
+	case AutoUpdateUser, AutoUpdateSystem:
+		return AutoUpdateScope(scope), nil
+	}
+	return "", fmt.Errorf("unknown scope %q, expected user or system", scope)
+}
+
+func (i *Installer) autoUpdatePaths(scope AutoUpdateScope) (string, string, error) {
+	if scope == AutoUpdateSystem {
+		return systemUnitDir, systemAutoUpdateLog, nil
+	}
+
+	homeDir, err := i.homeDir()
+	if err != nil {
+		return "", "", err
+	}
+	return filepath.Join(homeDir, ".config", "systemd", "user"),
+		filepath.Join(homeDir, ".local", "state", "cursor-installer", "auto-update.log"), nil
+}
+
+func (i *Installer) EnableAutoUpdate(ctx context.Context, scope AutoUpdateScope, schedule string) error {
+	if scope == AutoUpdateUser && os.Geteuid() == 0 {
+		return fmt.Errorf("user scope timers must be enabled without sudo, use --scope system instead")
+	}
+	if scope == AutoUpdateUser && commandContext(ctx, "sudo", "-n", "true").Run() != nil {
+		return fmt.Errorf("user scope timers install updates with sudo and cannot ask for a password, configure passwordless sudo for cursor-installer or use --scope system instead")
+	}
+	if err := validateSchedule(ctx, schedule); err != nil {
+		return err
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate cursor-installer executable: %v", err)
+	}
+	executable, err = filepath.EvalSymlinks(executable)
+	if err != nil {
+		return fmt.Errorf("failed to resolve cursor-installer executable: %v", err)
+	}
+
+	unitDir, logPath, err := i.autoUpdatePaths(scope)
+	if err != nil {
+		return err
+	}
+
+	// The system timer runs as root, so it must not run a binary the invoking
+	// user can still modify. Run a root-owned copy instead.
+	if scope == AutoUpdateSystem {
+		if err := runSudo(ctx, "install", "-D", "-o", "root", "-g", "root", "-m", "755", executable, systemAutoUpdateExe); err != nil {
+			return fmt.Errorf("failed to install %s (sudo error): %v", systemAutoUpdateExe, err)
+		}
+		executable = systemAutoUpdateExe
+	}
+
+	for _, path := range []string{executable, logPath} {
+		if strings.ContainsAny(path, " \t\n\"'\\%$") {
+			return fmt.Errorf("cannot write %s into a systemd unit, move it to a path without spaces, quotes, %% or $", path)
+		}
+	}
+
+	service := fmt.Sprintf(`[Unit]
+Description=Update the Cursor editor
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+Type=oneshot
+StateDirectory=cursor-installer
+WorkingDirectory=%%S/cursor-installer
+ExecStart=%s --non-interactive --wait
+StandardOutput=append:%s
+StandardError=append:%s
+`, executable, logPath, logPath)
+	if scope == AutoUpdateSystem {
+		service += "LogsDirectory=cursor-installer\n"
+	}
+
+	timer := fmt.Sprintf(`[Unit]
+Description=Periodically update the Cursor editor
+
+[Timer]
+OnCalendar=%s
+Persistent=true
+RandomizedDelaySec=15m
+
+[Install]
+WantedBy=timers.target
+`, schedule)
+
+	if scope == AutoUpdateUser {
+		if err := os.MkdirAll(unitDir, 0755); err != nil {
+			return fmt.Errorf("failed to create unit directory: %v", err)
+		}
+		if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+			return fmt.Errorf("failed to create log directory: %v", err)
+		}
+	}
+
+	if err := writeUnitFile(ctx, scope, filepath.Join(unitDir, autoUpdateUnit+".service"), service); err != nil {
+		return err
+	}
+	if err := writeUnitFile(ctx, scope, filepath.Join(unitDir, autoUpdateUnit+".timer"), timer); err != nil {
+		return err
+	}
+
+	if err := systemctl(ctx, scope, "daemon-reload"); err != nil {
+		return err
+	}
+	return systemctl(ctx, scope, "enable", "--now", autoUpdateUnit+".timer")
+}
+
+func (i *Installer) DisableAutoUpdate(ctx context.Context, scope AutoUpdateScope) error {
+	unitDir, _, err := i.autoUpdatePaths(scope)
+	if err != nil {
+		return err
+	}
+
+	timerPath := filepath.Join(unitDir, autoUpdateUnit+".timer")
+	if _, err := os.Stat(timerPath); os.IsNotExist(err) {
+		return fmt.Errorf("automatic updates are not enabled for %s scope", scope)
+	}
+
+	if err := systemctl(ctx, scope, "disable", "--now", autoUpdateUnit+".timer"); err != nil {
+		return err
+	}
+
+	for _, unit := range []string{autoUpdateUnit + ".timer", autoUpdateUnit + ".service"} {
+		path := filepath.Join(unitDir, unit)
+		if scope == AutoUpdateSystem {
+			if err := runSudo(ctx, "rm", "-f", path); err != nil {
+				return fmt.Errorf("failed to remove %s (sudo error): %v", unit, err)
+			}
+		} else if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %v", unit, err)
+		}
+	}
+	if scope == AutoUpdateSystem {
+		if err := runSudo(ctx, "rm", "-rf", filepath.Dir(systemAutoUpdateExe)); err != nil {
+			return fmt.Errorf("failed to remove %s (sudo error): %v", systemAutoUpdateExe, err)
+		}
+	}
+
+	return systemctl(ctx, scope, "daemon-reload")
+}
+
+func (i *Installer) AutoUpdateStatus(scope AutoUpdateScope) (*AutoUpdateStatus, error) {
+	unitDir, logPath, err := i.autoUpdatePaths(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &AutoUpdateStatus{Scope: scope, LogPath: logPath}
+
+	timerPath := filepath.Join(unitDir, autoUpdateUnit+".timer")
+	data, err := os.ReadFile(timerPath)
+	if os.IsNotExist(err) {
+		return status, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read timer unit: %v", err)
+	}
+	status.Installed = true
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if value, ok := strings.CutPrefix(line, "OnCalendar="); ok {
+			status.Schedule = value
+		}
+	}
+
+	status.Enabled = systemctlOutput(scope, "is-enabled", autoUpdateUnit+".timer")
+	status.Active = systemctlOutput(scope, "is-active", autoUpdateUnit+".timer")
+
+	properties := systemctlOutput(scope, "show", autoUpdateUnit+".timer", "--property=NextElapseUSecRealtime,LastTriggerUSec")
+	for _, line := range strings.Split(properties, "\n") {
+		if value, ok := strings.CutPrefix(line, "NextElapseUSecRealtime="); ok {
+			status.NextRun = value
+		}
+		if value, ok := strings.CutPrefix(line, "LastTriggerUSec="); ok {
+			status.LastRun = value
+		}
+	}
+
+	status.RecentLog, err = tailFile(logPath, 20)
+	if err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+func validateSchedule(ctx context.Context, schedule string) error {
+	if schedule == "" || strings.ContainsAny(schedule, "\n\r") {
+		return fmt.Errorf("invalid schedule %q", schedule)
+	}
+	if output, err := commandContext(ctx, "systemd-analyze", "calendar", schedule).CombinedOutput(); err != nil {
+		return fmt.Errorf("invalid schedule %q: %s", schedule, strings.TrimSpace(lastLine(string(output))))
+	}
+	return nil
+}
+
+func writeUnitFile(ctx context.Context, scope AutoUpdateScope, path, content string) error {
+	if scope == AutoUpdateUser {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %v", filepath.Base(path), err)
+		}
+		return nil
+	}
+
+	tmpFile, err := os.CreateTemp("", "cursor-unit-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.WriteString(content); err != nil {
+		return fmt.Errorf("failed to write %s: %v", filepath.Base(path), err)
+	}
+	tmpFile.Close()
+
+	if err := runSudo(ctx, "install", "-m", "644", tmpFile.Name(), path); err != nil {
+		return fmt.Errorf("failed to install %s (sudo error): %v", filepath.Base(path), err)
+	}
+
+	return nil
+}
+
+func systemctl(ctx context.Context, scope AutoUpdateScope, args ...string) error {
+	var cmd *exec.Cmd
+	if scope == AutoUpdateUser {
+		cmd = commandContext(ctx, "systemctl", append([]string{"--user"}, args...)...)
+		cmd.Stdout = stdout(ctx)
+		cmd.Stderr = stderr(ctx)
+	} else {
+		cmd = sudoCommand(ctx, append([]string{"systemctl"}, args...)...)
+	}
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("systemctl %s failed: %v", strings.Join(args, " "), err)
+	}
+	return nil
+}
+
+func systemctlOutput(scope AutoUpdateScope, args ...string) string {
+	if scope == AutoUpdateUser {
+		args = append([]string{"--user"}, args...)
+	}
+	output, _ := exec.Command("systemctl", args...).Output()
+	return strings.TrimSpace(string(output))
+}
+
+func tailFile(path string, lines int) ([]string, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	var tail []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		tail = append(tail, scanner.Text())
+		if len(tail) > lines {
+			tail = tail[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+
+	return tail, nil
+}
@@ -0,0 +1,216 @@
+package app
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+const systemCacheDir = "/var/cache/cursor-installer"
+
+type CacheEntry struct {
+	Version  string
+	Checksum string
+	Path     string
+	Size     int64
+	Modified time.Time
+}
+
+func (i *Installer) SetCacheRetention(keep int) {
+	i.cacheRetention = keep
+}
+
+func (i *Installer) CacheDir() (string, error) {
+	if cacheDirOverride != "" {
+		return cacheDirOverride, nil
+	}
+	if unix.Access(systemCacheDir, unix.W_OK) == nil {
+		return systemCacheDir, nil
+	}
+	if os.Geteuid() == 0 {
+		if err := os.MkdirAll(systemCacheDir, 0755); err == nil {
+			return systemCacheDir, nil
+		}
+	}
+
+	if cacheHome := os.Getenv("XDG_CACHE_HOME"); cacheHome != "" && os.Geteuid() != 0 {
+		return filepath.Join(cacheHome, "cursor-installer"), nil
+	}
+	homeDir, err := i.homeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".cache", "cursor-installer"), nil
+}
+
+func (i *Installer) CacheEntries() ([]CacheEntry, error) {
+	dir, err := i.CacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*.AppImage"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache: %v", err)
+	}
+
+	var entries []CacheEntry
+	for _, match := range matches {
+		name := strings.TrimSuffix(filepath.Base(match), ".AppImage")
+		separator := strings.LastIndex(name, "_")
+		if separator < 0 || len(name)-separator-1 != sha256.Size*2 {
+			continue
+		}
+		version, checksum := name[:separator], name[separator+1:]
+		if validateVersion(version) != nil {
+			continue
+		}
+		info, err := os.Stat(match)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, CacheEntry{
+			Version:  version,
+			Checksum: checksum,
+			Path:     match,
+			Size:     info.Size(),
+			Modified: info.ModTime(),
+		})
+	}
+
+	sort.Slice(entries, func(a, b int) bool { return entries[a].Modified.After(entries[b].Modified) })
+	return entries, nil
+}
+
+func (i *Installer) cachedDownload(version string) (*CacheEntry, error) {
+	entries, err := i.CacheEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.Version != version {
+			continue
+		}
+		checksum, err := fileChecksum(entry.Path)
+		if err == nil && checksum == entry.Checksum {
+			now := time.Now()
+			os.Chtimes(entry.Path, now, now)
+			return &entry, nil
+		}
+		os.Remove(entry.Path)
+	}
+
+	return nil, nil
+}
+
+func (i *Installer) storeInCache(version, source, checksum string) error {
+	if err := validateVersion(version); err != nil {
+		return err
+	}
+	dir, err := i.CacheDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %v", err)
+	}
+
+	target := filepath.Join(dir, fmt.Sprintf("%s_%s.AppImage", version, checksum))
+	if _, err := os.Stat(target); err == nil {
+		return nil
+	}
+
+	if err := copyFile(source, target+".partial"); err != nil {
+		os.Remove(target + ".partial")
+		return err
+	}
+	if err := os.Rename(target+".partial", target); err != nil {
+		os.Remove(target + ".partial")
+		return fmt.Errorf("failed to store download in cache: %v", err)
+	}
+
+	if i.cacheRetention > 0 {
+		_, err = i.PruneCache(i.cacheRetention)
+		return err
+	}
+	return nil
+}
+
+func (i *Installer) PruneCache(keep int) ([]CacheEntry, error) {
+	entries, err := i.CacheEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []CacheEntry
+	kept := make(map[string]bool)
+	for _, entry := range entries {
+		if !kept[entry.Version] && len(kept) < keep {
+			kept[entry.Version] = true
+			continue
+		}
+		if err := os.Remove(entry.Path); err != nil {
+			return removed, fmt.Errorf("failed to remove %s: %v", entry.Path, err)
+		}
+		removed = append(removed, entry)
+	}
+
+	return removed, i.removePartialDownloads()
+}
+
+func (i *Installer) ClearCache() ([]CacheEntry, error) {
+	entries, err := i.CacheEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	for idx, entry := range entries {
+		if err := os.Remove(entry.Path); err != nil {
+			return entries[:idx], fmt.Errorf("failed to remove %s: %v", entry.Path, err)
+		}
+	}
+
+	return entries, i.removePartialDownloads()
+}
+
+func (i *Installer) removePartialDownloads() error {
+	dir, err := i.CacheDir()
+	if err != nil {
+		return err
+	}
+
+	partials, _ := filepath.Glob(filepath.Join(dir, "*.partial"))
+	for _, partial := range partials {
+		if err := os.Remove(partial); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %v", partial, err)
+		}
+	}
+	return nil
+}
+
+func copyFile(source, target string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %v", source, err)
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", target, err)
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return fmt.Errorf("failed to copy %s: %v", source, err)
+	}
+	return out.Close()
+}
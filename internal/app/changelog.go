@@ -0,0 +1,185 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const (
+	changelogURL  = "https://www.cursor.com/changelog"
+	changelogPath = "/changelog"
+)
+
+var (
+	changelogVersion = regexp.MustCompile(`\d+\.\d+(?:\.\d+|\.x)?`)
+	htmlHeading      = regexp.MustCompile(`(?is)<h[1-3][^>]*>(.*?)</h[1-3]>`)
+	htmlListItem     = regexp.MustCompile(`(?i)<li[^>]*>`)
+	htmlBreak        = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>|</ul>`)
+	htmlTag          = regexp.MustCompile(`(?s)<[^>]*>`)
+	htmlDropped      = regexp.MustCompile(`(?is)<(script|style|head|nav|footer)[^>]*>.*?</(script|style|head|nav|footer)>`)
+	blankLines       = regexp.MustCompile(`\n{3,}`)
+)
+
+type ReleaseNotes struct {
+	Version string
+	Title   string
+	Notes   string
+}
+
+type ReleaseNotesError struct {
+	From     string
+	To       string
+	Releases []ReleaseNotes
+}
+
+func (e *ReleaseNotesError) Error() string {
+	return fmt.Sprintf("Cursor %s is available", e.To)
+}
+
+func (i *Installer) SetChangelogSource(source string) {
+	i.changelogSource = source
+}
+
+func (i *Installer) changelogLocation() string {
+	if i.changelogSource != "" {
+		return i.changelogSource
+	}
+	if i.mirror != "" {
+		if location := i.mirrorURL(changelogPath); location != "" {
+			return location
+		}
+	}
+	return changelogURL
+}
+
+func (i *Installer) readChangelog(ctx context.Context) ([]byte, error) {
+	location := i.changelogLocation()
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		data, err := os.ReadFile(location)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read changelog: %v", err)
+		}
+		return data, nil
+	}
+
+	resp, err := i.httpGet(ctx, location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch changelog: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch changelog: %s returned %s", location, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 8<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read changelog: %v", err)
+	}
+	return data, nil
+}
+
+func (i *Installer) ReleaseNotes(ctx context.Context) ([]ReleaseNotes, error) {
+	to, _ := i.GetLatestVersion()
+	if to == "unknown" {
+		return nil, fmt.Errorf("the latest version is unknown")
+	}
+
+	data, err := i.readChangelog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var releases []ReleaseNotes
+	for _, release := range parseChangelog(string(data)) {
+		if releaseInRange(release.Version, i.previousVersion, to) {
+			releases = append(releases, release)
+		}
+	}
+	return releases, nil
+}
+
+func (i *Installer) CheckReleaseNotes(ctx context.Context) error {
+	releases, err := i.ReleaseNotes(ctx)
+	if err != nil {
+		i.warn(ctx, fmt.Sprintf("Release notes are unavailable: %v", err))
+		return nil
+	}
+	if len(releases) == 0 {
+		return nil
+	}
+
+	to, _ := i.GetLatestVersion()
+	return &ReleaseNotesError{From: i.previousVersion, To: to, Releases: releases}
+}
+
+func parseChangelog(text string) []ReleaseNotes {
+	if strings.Contains(text, "<h1") || strings.Contains(text, "<h2") || strings.Contains(text, "<h3") {
+		text = htmlToMarkdown(text)
+	}
+
+	var releases []ReleaseNotes
+	var notes []string
+	flush := func() {
+		if len(releases) > 0 {
+			releases[len(releases)-1].Notes = strings.TrimSpace(strings.Join(notes, "\n"))
+		}
+		notes = nil
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") {
+			title := strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			if version := changelogVersion.FindString(title); version != "" {
+				flush()
+				releases = append(releases, ReleaseNotes{Version: version, Title: title})
+				continue
+			}
+		}
+		if len(releases) > 0 {
+			notes = append(notes, strings.TrimRight(line, " \t"))
+		}
+	}
+	flush()
+
+	return releases
+}
+
+func htmlToMarkdown(text string) string {
+	text = htmlDropped.ReplaceAllString(text, "")
+	text = htmlHeading.ReplaceAllStringFunc(text, func(heading string) string {
+		inner := htmlHeading.FindStringSubmatch(heading)[1]
+		return "\n## " + strings.Join(strings.Fields(htmlTag.ReplaceAllString(inner, " ")), " ") + "\n"
+	})
+	text = htmlListItem.ReplaceAllString(text, "\n- ")
+	text = htmlBreak.ReplaceAllString(text, "\n")
+	text = html.UnescapeString(htmlTag.ReplaceAllString(text, ""))
+
+	lines := strings.Split(text, "\n")
+	for idx, line := range lines {
+		lines[idx] = strings.TrimSpace(line)
+	}
+	return blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+}
+
+func releaseInRange(version, from, to string) bool {
+	version = strings.TrimSuffix(version, ".x")
+	if compareVersions(version, to) > 0 && !versionPrefix(version, to) {
+		return false
+	}
+	if from == "" {
+		return compareVersions(version, to) == 0 || versionPrefix(version, to)
+	}
+	return compareVersions(version, from) > 0 && !versionPrefix(version, from)
+}
+
+func versionPrefix(prefix, version string) bool {
+	return version == prefix || strings.HasPrefix(version, prefix+".")
+}
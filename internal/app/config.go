@@ -1,48 +1,97 @@
 package app
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 )
 
 type CursorConfig struct {
-	DisabledLanguages          []string `json:"cursor.cpp.disabledLanguages,omitempty"`
-	EnablePartialAccepts       bool     `json:"cursor.cpp.enablePartialAccepts,omitempty"`
-	UsePreviewBox              bool     `json:"cursor.terminal.usePreviewBox,omitempty"`
-	RenderPillsInsteadOfBlocks bool     `json:"cursor.composer.renderPillsInsteadOfBlocks,omitempty"`
-	CommandAllowlist           []string `json:"cursor.terminal.commandAllowlist,omitempty"`
-	RequireApproval            bool     `json:"cursor.terminal.requireApproval,omitempty"`
-	EnableYoloMode             bool     `json:"cursor.terminal.enableYoloMode,omitempty"`
+	DisabledLanguages          []string `json:"cursor.cpp.disabledLanguages,omitempty" desc:"Languages where Cursor Tab completions are disabled"`
+	EnablePartialAccepts       bool     `json:"cursor.cpp.enablePartialAccepts,omitempty" desc:"Accept Cursor Tab suggestions word by word"`
+	UsePreviewBox              bool     `json:"cursor.terminal.usePreviewBox,omitempty" desc:"Preview terminal commands before running them"`
+	RenderPillsInsteadOfBlocks bool     `json:"cursor.composer.renderPillsInsteadOfBlocks,omitempty" desc:"Show composer code changes as compact pills"`
+	CommandAllowlist           []string `json:"cursor.terminal.commandAllowlist,omitempty" desc:"Commands the agent may run without asking"`
+	RequireApproval            bool     `json:"cursor.terminal.requireApproval,omitempty" desc:"Ask before the agent runs any terminal command"`
+	EnableYoloMode             bool     `json:"cursor.terminal.enableYoloMode,omitempty" desc:"Let the agent run allowlisted commands automatically"`
 }
 
-func (i *Installer) ConfigureCursor() error {
-	homeDir, err := os.UserHomeDir()
+type SettingKind string
+
+const (
+	SettingBool   SettingKind = "bool"
+	SettingString SettingKind = "string"
+	SettingList   SettingKind = "list"
+)
+
+type CursorSetting struct {
+	Key         string
+	Description string
+	Kind        SettingKind
+	Value       interface{}
+	IsSet       bool
+}
+
+func (i *Installer) CursorSettings() ([]CursorSetting, error) {
+	current, err := i.ReadSettings()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %v", err)
+		return nil, err
 	}
 
-	configDir := filepath.Join(homeDir, ".config", "Cursor", "User")
-	settingsPath := filepath.Join(configDir, "settings.json")
+	configType := reflect.TypeOf(CursorConfig{})
+	settings := make([]CursorSetting, 0, configType.NumField())
+	for n := 0; n < configType.NumField(); n++ {
+		field := configType.Field(n)
+		key := strings.Split(field.Tag.Get("json"), ",")[0]
 
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return fmt.Errorf("failed to create config directory: %v", err)
-	}
+		setting := CursorSetting{
+			Key:         key,
+			Description: field.Tag.Get("desc"),
+		}
 
-	var settings map[string]interface{}
-	if data, err := os.ReadFile(settingsPath); err == nil {
-		if err := json.Unmarshal(data, &settings); err != nil {
-			return fmt.Errorf("failed to parse existing settings: %v", err)
+		value, isSet := current[key]
+		switch field.Type.Kind() {
+		case reflect.Bool:
+			setting.Kind = SettingBool
+			enabled, ok := value.(bool)
+			setting.Value = enabled
+			setting.IsSet = isSet && ok
+		case reflect.Slice:
+			setting.Kind = SettingList
+			items := []string{}
+			if values, ok := value.([]interface{}); ok {
+				for _, item := range values {
+					items = append(items, fmt.Sprint(item))
+				}
+			}
+			setting.Value = items
+			setting.IsSet = isSet
+		default:
+			setting.Kind = SettingString
+			text, ok := value.(string)
+			setting.Value = text
+			setting.IsSet = isSet && ok
 		}
-	} else if !os.IsNotExist(err) {
-		return fmt.Errorf("failed to read settings file: %v", err)
+
+		settings = append(settings, setting)
 	}
 
-	if settings == nil {
-		settings = make(map[string]interface{})
+	return settings, nil
+}
+
+func (i *Installer) cursorUserDir() (string, error) {
+	homeDir, err := i.homeDir()
+	if err != nil {
+		return "", err
 	}
+	return filepath.Join(homeDir, ".config", "Cursor", "User"), nil
+}
 
+func (i *Installer) ConfigureCursor() error {
 	config := CursorConfig{
 		DisabledLanguages:          []string{"scminput", "yaml"},
 		EnablePartialAccepts:       true,
@@ -62,7 +111,47 @@ func (i *Installer) ConfigureCursor() error {
 		return fmt.Errorf("failed to convert config to map: %v", err)
 	}
 
-	for k, v := range configMap {
+	return i.MergeSettings(configMap)
+}
+
+func (i *Installer) ReadSettings() (map[string]interface{}, error) {
+	configDir, err := i.cursorUserDir()
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := readSettingsFile(filepath.Join(configDir, "settings.json"))
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		settings = make(map[string]interface{})
+	}
+	return settings, nil
+}
+
+func (i *Installer) MergeSettings(values map[string]interface{}) error {
+	configDir, err := i.cursorUserDir()
+	if err != nil {
+		return err
+	}
+	settingsPath := filepath.Join(configDir, "settings.json")
+
+	if err := i.mkdirAllOwned(configDir); err != nil {
+		return fmt.Errorf("failed to create config directory: %v", err)
+	}
+
+	settings, err := readSettingsFile(settingsPath)
+	if err != nil {
+		return err
+	}
+
+	if settings == nil {
+		settings = make(map[string]interface{})
+	}
+
+	changed := changedKeys(settings, values)
+	for k, v := range values {
 		settings[k] = v
 	}
 
@@ -71,9 +160,139 @@ func (i *Installer) ConfigureCursor() error {
 		return fmt.Errorf("failed to marshal settings: %v", err)
 	}
 
-	if err := os.WriteFile(settingsPath, data, 0644); err != nil {
+	if err := i.writeUserFile(settingsPath, data, 0644); err != nil {
 		return fmt.Errorf("failed to write settings file: %v", err)
 	}
+	i.recordSettings(settingsPath, changed)
+
+	return nil
+}
+
+func (i *Installer) ReadKeybindings() ([]interface{}, error) {
+	configDir, err := i.cursorUserDir()
+	if err != nil {
+		return nil, err
+	}
+	return readKeybindingsFile(filepath.Join(configDir, "keybindings.json"))
+}
+
+func (i *Installer) MergeKeybindings(entries []interface{}) error {
+	configDir, err := i.cursorUserDir()
+	if err != nil {
+		return err
+	}
+	keybindingsPath := filepath.Join(configDir, "keybindings.json")
+
+	if err := i.mkdirAllOwned(configDir); err != nil {
+		return fmt.Errorf("failed to create config directory: %v", err)
+	}
+
+	keybindings, err := readKeybindingsFile(keybindingsPath)
+	if err != nil {
+		return err
+	}
+
+	keybindings = append(keybindings, missingKeybindings(keybindings, entries)...)
+
+	data, err := json.MarshalIndent(keybindings, "", "\t")
+	if err != nil {
+		return fmt.Errorf("failed to marshal keybindings: %v", err)
+	}
+
+	if err := i.writeUserFile(keybindingsPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write keybindings file: %v", err)
+	}
 
 	return nil
 }
+
+func readSettingsFile(path string) (map[string]interface{}, error) {
+	var settings map[string]interface{}
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(stripJSONComments(data), &settings); err != nil {
+			return nil, fmt.Errorf("failed to parse existing settings: %v", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read settings file: %v", err)
+	}
+	return settings, nil
+}
+
+func readKeybindingsFile(path string) ([]interface{}, error) {
+	var keybindings []interface{}
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(stripJSONComments(data), &keybindings); err != nil {
+			return nil, fmt.Errorf("failed to parse existing keybindings: %v", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read keybindings file: %v", err)
+	}
+	return keybindings, nil
+}
+
+func missingKeybindings(existing, entries []interface{}) []interface{} {
+	var missing []interface{}
+	for _, entry := range entries {
+		found := false
+		for _, current := range existing {
+			if reflect.DeepEqual(current, entry) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, entry)
+		}
+	}
+	return missing
+}
+
+// VS Code writes settings and keybindings as JSONC, so comments and trailing
+// commas have to go before encoding/json will accept them.
+func stripJSONComments(data []byte) []byte {
+	var out bytes.Buffer
+	inString := false
+	for n := 0; n < len(data); n++ {
+		c := data[n]
+		if inString {
+			out.WriteByte(c)
+			if c == '\\' && n+1 < len(data) {
+				n++
+				out.WriteByte(data[n])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+			out.WriteByte(c)
+		case c == '/' && n+1 < len(data) && data[n+1] == '/':
+			for n < len(data) && data[n] != '\n' {
+				n++
+			}
+			if n < len(data) {
+				out.WriteByte('\n')
+			}
+		case c == '/' && n+1 < len(data) && data[n+1] == '*':
+			n += 2
+			for n+1 < len(data) && !(data[n] == '*' && data[n+1] == '/') {
+				n++
+			}
+			n++
+		case c == ']' || c == '}':
+			trimmed := bytes.TrimRight(out.Bytes(), " \t\r\n")
+			if len(trimmed) > 0 && trimmed[len(trimmed)-1] == ',' {
+				tail := append([]byte(nil), out.Bytes()[len(trimmed):]...)
+				out.Truncate(len(trimmed) - 1)
+				out.Write(tail)
+			}
+			out.WriteByte(c)
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.Bytes()
+}
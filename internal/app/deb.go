@@ -0,0 +1,113 @@
+package app
+
+import (
+	"crypto/md5"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+func writeDeb(target string, p *PackageSource, files []packageFile, maintainer string) error {
+	now := time.Now()
+
+	var md5sums strings.Builder
+	for _, file := range files {
+		if !file.mode.IsRegular() {
+			continue
+		}
+		sum, err := fileDigest(file, md5.New())
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&md5sums, "%s  %s\n", sum, strings.TrimPrefix(file.path, "/"))
+	}
+
+	control := fmt.Sprintf(`Package: %s
+Version: %s-%s
+Architecture: %s
+Maintainer: %s
+Installed-Size: %d
+Depends: libfuse2 | libfuse2t64
+Section: devel
+Priority: optional
+Homepage: %s
+Description: %s
+ %s
+`, packageName, p.Version, packageRelease, p.Arch, maintainer, (installedSize(files)+1023)/1024, packageURL, packageSummary, packageDescription)
+
+	controlFiles := []packageFile{
+		{path: "/control", mode: 0644, data: []byte(control), size: int64(len(control))},
+		{path: "/md5sums", mode: 0644, data: []byte(md5sums.String()), size: int64(md5sums.Len())},
+	}
+
+	controlArchive, err := os.CreateTemp("", "cursor-deb-control-*.tar.gz")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	defer os.Remove(controlArchive.Name())
+	defer controlArchive.Close()
+	if err := writeTarGz(controlArchive, "./", controlFiles, now); err != nil {
+		return err
+	}
+
+	dataArchive, err := os.CreateTemp("", "cursor-deb-data-*.tar.gz")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	defer os.Remove(dataArchive.Name())
+	defer dataArchive.Close()
+	if err := writeTarGz(dataArchive, "./", files, now); err != nil {
+		return err
+	}
+
+	out, err := os.Create(target)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", target, err)
+	}
+	defer out.Close()
+
+	if _, err := io.WriteString(out, "!<arch>\n"); err != nil {
+		return fmt.Errorf("failed to write %s: %v", target, err)
+	}
+	if err := writeArMember(out, "debian-binary", strings.NewReader("2.0\n"), 4, now); err != nil {
+		return err
+	}
+	for _, member := range []struct {
+		name    string
+		archive *os.File
+	}{
+		{"control.tar.gz", controlArchive},
+		{"data.tar.gz", dataArchive},
+	} {
+		info, err := member.archive.Stat()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %v", member.name, err)
+		}
+		if _, err := member.archive.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("failed to read %s: %v", member.name, err)
+		}
+		if err := writeArMember(out, member.name, member.archive, info.Size(), now); err != nil {
+			return err
+		}
+	}
+
+	return out.Close()
+}
+
+func writeArMember(w io.Writer, name string, data io.Reader, size int64, modTime time.Time) error {
+	header := fmt.Sprintf("%-16s%-12d%-6d%-6d%-8s%-10d`\n", name, modTime.Unix(), 0, 0, "100644", size)
+	if _, err := io.WriteString(w, header); err != nil {
+		return fmt.Errorf("failed to write %s: %v", name, err)
+	}
+	if _, err := io.Copy(w, data); err != nil {
+		return fmt.Errorf("failed to write %s: %v", name, err)
+	}
+	if size%2 == 1 {
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return fmt.Errorf("failed to write %s: %v", name, err)
+		}
+	}
+	return nil
+}
@@ -1,13 +1,17 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 )
 
-func (i *Installer) ExtractIcon() error {
+func (i *Installer) ExtractIcon(ctx context.Context) error {
+	if i.extracted() {
+		return i.installIcon(ctx, filepath.Join(i.extractedDir(), extractedIcon))
+	}
+
 	tempDir, err := os.MkdirTemp("", "cursor-icon")
 	if err != nil {
 		return fmt.Errorf("failed to create temp directory: %v", err)
@@ -24,44 +28,45 @@ func (i *Installer) ExtractIcon() error {
 	}
 	defer os.Chdir(currentDir)
 
-	cmd := exec.Command(filepath.Join(installDir, appImage), "--appimage-extract")
+	cmd := commandContext(ctx, i.appImagePath(), "--appimage-extract")
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to extract AppImage: %v", err)
 	}
 
-	iconPath := "squashfs-root/usr/share/icons/hicolor/512x512/apps/cursor.png"
+	return i.installIcon(ctx, filepath.Join("squashfs-root", extractedIcon))
+}
+
+func (i *Installer) installIcon(ctx context.Context, iconPath string) error {
 	if _, err := os.Stat(iconPath); err != nil {
 		return fmt.Errorf("icon not found in extracted contents: %v", err)
 	}
 
-	targetPath := filepath.Join(installDir, "cursor.png")
-	cmd = exec.Command("sudo", "-S", "cp", iconPath, targetPath)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+	targetPath := filepath.Join(installDir, iconFile)
+	existed := pathExists(targetPath)
+	if err := runSudo(ctx, "cp", iconPath, targetPath); err != nil {
 		return fmt.Errorf("failed to copy icon (sudo error): %v", err)
 	}
 
-	cmd = exec.Command("sudo", "-S", "chmod", "644", targetPath)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+	if err := runSudo(ctx, "chmod", "644", targetPath); err != nil {
 		return fmt.Errorf("failed to set icon permissions (sudo error): %v", err)
 	}
+	i.recordFile(targetPath, existed)
 
 	return nil
 }
 
-func (i *Installer) CreateDesktopEntry() error {
-	desktopEntry := fmt.Sprintf(`[Desktop Entry]
+func desktopEntry(execPath, icon string) string {
+	return fmt.Sprintf(`[Desktop Entry]
 Name=Cursor
 Exec=%s
 Icon=%s
 Type=Application
 Categories=Development;
-`, filepath.Join(installDir, appImage), filepath.Join(installDir, "cursor.png"))
+`, execPath, icon)
+}
+
+func (i *Installer) CreateDesktopEntry(ctx context.Context) error {
+	desktopEntry := desktopEntry(i.launchPath(), filepath.Join(installDir, iconFile))
 
 	tmpFile, err := os.CreateTemp("", "cursor-*.desktop")
 	if err != nil {
@@ -74,32 +79,24 @@ Categories=Development;
 	}
 	tmpFile.Close()
 
-	cmd := exec.Command("sudo", "-S", "mv", tmpFile.Name(), "/usr/share/applications/cursor.desktop")
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+	existed := pathExists(desktopEntryPath)
+	if err := runSudo(ctx, "mv", tmpFile.Name(), desktopEntryPath); err != nil {
 		return fmt.Errorf("failed to install desktop entry (sudo error): %v", err)
 	}
 
-	cmd = exec.Command("sudo", "-S", "chmod", "644", "/usr/share/applications/cursor.desktop")
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+	if err := runSudo(ctx, "chmod", "644", desktopEntryPath); err != nil {
 		return fmt.Errorf("failed to set desktop entry permissions (sudo error): %v", err)
 	}
+	i.recordFile(desktopEntryPath, existed)
 
 	return nil
 }
 
-func (i *Installer) CreateSymlink() error {
-	cmd := exec.Command("sudo", "-S", "ln", "-sf", filepath.Join(installDir, appImage), "/usr/local/bin/cursor")
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+func (i *Installer) CreateSymlink(ctx context.Context) error {
+	existed := pathExists(symlinkPath)
+	if err := runSudo(ctx, "ln", "-sf", i.launchPath(), symlinkPath); err != nil {
 		return fmt.Errorf("failed to create symlink (sudo error): %v", err)
 	}
+	i.recordFile(symlinkPath, existed)
 	return nil
 }
@@ -0,0 +1,340 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+type CheckStatus string
+
+const (
+	CheckPass CheckStatus = "pass"
+	CheckWarn CheckStatus = "warn"
+	CheckFail CheckStatus = "fail"
+)
+
+type DiagnosticResult struct {
+	Name   string
+	Status CheckStatus
+	Detail string
+	Fix    string
+}
+
+var fuseLibraryPaths = []string{
+	"/usr/lib/x86_64-linux-gnu/libfuse.so.2",
+	"/usr/lib/aarch64-linux-gnu/libfuse.so.2",
+	"/lib/x86_64-linux-gnu/libfuse.so.2",
+	"/usr/lib64/libfuse.so.2",
+	"/usr/lib/libfuse.so.2",
+	"/lib64/libfuse.so.2",
+}
+
+func (i *Installer) RunDiagnostics(ctx context.Context) []DiagnosticResult {
+	metadata, metadataErr := i.readMetadata(ctx)
+	i.applyMetadataLayout(metadata)
+
+	if i.extracted() {
+		return []DiagnosticResult{
+			{Name: "FUSE", Status: CheckPass, Detail: "not required for extracted installs"},
+			checkBinary("AppRun", i.installedBinary()),
+			checkSymlink(metadata),
+			checkDesktopEntry(),
+			checkIcon(),
+			checkMetadata(metadata, metadataErr, i.extractedDir()),
+			checkSandbox(),
+		}
+	}
+
+	return []DiagnosticResult{
+		checkFuse(),
+		checkBinary("AppImage", filepath.Join(installDir, appImage)),
+		checkSymlink(metadata),
+		checkDesktopEntry(),
+		checkIcon(),
+		checkMetadata(metadata, metadataErr, filepath.Join(installDir, appImage)),
+		checkChecksum(metadata),
+		checkSandbox(),
+	}
+}
+
+func checkFuse() DiagnosticResult {
+	result := DiagnosticResult{Name: "FUSE"}
+
+	found := ""
+	for _, path := range fuseLibraryPaths {
+		if _, err := os.Stat(path); err == nil {
+			found = path
+			break
+		}
+	}
+	if found == "" {
+		if output, err := exec.Command("ldconfig", "-p").Output(); err == nil && strings.Contains(string(output), "libfuse.so.2") {
+			found = "ldconfig cache"
+		}
+	}
+
+	if found == "" {
+		result.Status = CheckFail
+		result.Detail = "libfuse.so.2 not found, AppImages cannot be mounted"
+		result.Fix = "Install libfuse2 (Debian/Ubuntu: sudo apt install libfuse2, Fedora: sudo dnf install fuse-libs), or reinstall with cursor-installer --force --extract"
+		return result
+	}
+
+	if _, err := os.Stat("/dev/fuse"); err != nil {
+		result.Status = CheckFail
+		result.Detail = "/dev/fuse is not available"
+		result.Fix = "Load the fuse kernel module with sudo modprobe fuse"
+		return result
+	}
+
+	result.Status = CheckPass
+	result.Detail = fmt.Sprintf("libfuse.so.2 found (%s)", found)
+	return result
+}
+
+func checkBinary(name, path string) DiagnosticResult {
+	result := DiagnosticResult{Name: name}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		result.Status = CheckFail
+		result.Detail = fmt.Sprintf("%s is missing", path)
+		result.Fix = "Reinstall Cursor with cursor-installer --force"
+		return result
+	}
+
+	if info.Mode().Perm()&0111 == 0 {
+		result.Status = CheckFail
+		result.Detail = fmt.Sprintf("%s is not executable (%s)", path, info.Mode().Perm())
+		result.Fix = "Run cursor-installer repair"
+		return result
+	}
+
+	result.Status = CheckPass
+	result.Detail = fmt.Sprintf("%s (%s)", path, info.Mode().Perm())
+	return result
+}
+
+func checkSymlink(metadata *CursorMetadata) DiagnosticResult {
+	result := DiagnosticResult{Name: "Symlink"}
+
+	expected := filepath.Join(installDir, appImage)
+	if metadata != nil && metadata.LaunchPath != "" {
+		expected = metadata.LaunchPath
+	}
+
+	target, err := os.Readlink(symlinkPath)
+	if err != nil {
+		result.Status = CheckFail
+		result.Detail = fmt.Sprintf("%s is missing or not a symlink", symlinkPath)
+		result.Fix = "Run cursor-installer repair"
+		return result
+	}
+
+	if target != expected {
+		result.Status = CheckWarn
+		result.Detail = fmt.Sprintf("%s points to %s instead of %s", symlinkPath, target, expected)
+		result.Fix = "Run cursor-installer repair"
+		return result
+	}
+
+	result.Status = CheckPass
+	result.Detail = fmt.Sprintf("%s → %s", symlinkPath, target)
+	return result
+}
+
+func checkDesktopEntry() DiagnosticResult {
+	result := DiagnosticResult{Name: "Desktop entry"}
+
+	entry, err := readDesktopEntry(desktopEntryPath)
+	if err != nil {
+		result.Status = CheckFail
+		result.Detail = fmt.Sprintf("%s is missing or unreadable", desktopEntryPath)
+		result.Fix = "Run cursor-installer repair"
+		return result
+	}
+
+	var problems []string
+	if entry["Type"] != "Application" {
+		problems = append(problems, "Type is not Application")
+	}
+	if entry["Name"] == "" {
+		problems = append(problems, "Name is empty")
+	}
+	if exec := desktopEntryExec(entry); exec == "" {
+		problems = append(problems, "Exec is empty")
+	} else if _, err := os.Stat(exec); err != nil {
+		problems = append(problems, fmt.Sprintf("Exec target %s does not exist", exec))
+	}
+	if icon := entry["Icon"]; icon == "" {
+		problems = append(problems, "Icon is empty")
+	} else if filepath.IsAbs(icon) {
+		if _, err := os.Stat(icon); err != nil {
+			problems = append(problems, fmt.Sprintf("Icon %s does not exist", icon))
+		}
+	}
+
+	if len(problems) > 0 {
+		result.Status = CheckFail
+		result.Detail = strings.Join(problems, "; ")
+		result.Fix = "Run cursor-installer repair"
+		return result
+	}
+
+	result.Status = CheckPass
+	result.Detail = desktopEntryPath
+	return result
+}
+
+func checkIcon() DiagnosticResult {
+	result := DiagnosticResult{Name: "Icon"}
+	path := filepath.Join(installDir, iconFile)
+
+	if _, err := os.Stat(path); err != nil {
+		result.Status = CheckWarn
+		result.Detail = fmt.Sprintf("%s is missing", path)
+		result.Fix = "Run cursor-installer repair"
+		return result
+	}
+
+	result.Status = CheckPass
+	result.Detail = path
+	return result
+}
+
+func checkMetadata(metadata *CursorMetadata, metadataErr error, installPath string) DiagnosticResult {
+	result := DiagnosticResult{Name: "Metadata"}
+
+	if metadataErr != nil {
+		result.Status = CheckFail
+		result.Detail = metadataErr.Error()
+		result.Fix = fmt.Sprintf("Check the permissions of %s or reinstall with cursor-installer --force", metadataPath)
+		return result
+	}
+
+	if metadata == nil {
+		result.Status = CheckWarn
+		result.Detail = fmt.Sprintf("%s not found, the installed version is unknown", metadataPath)
+		result.Fix = "Run cursor-installer repair to record installation metadata"
+		return result
+	}
+
+	if metadata.Version == "" || metadata.Version == "unknown" {
+		result.Status = CheckWarn
+		result.Detail = "installed version was not recorded"
+		result.Fix = "Reinstall with cursor-installer --force to record the version"
+		return result
+	}
+
+	if metadata.InstallPath != installPath {
+		result.Status = CheckWarn
+		result.Detail = fmt.Sprintf("install path %s does not match %s", metadata.InstallPath, installPath)
+		result.Fix = "Reinstall with cursor-installer --force"
+		return result
+	}
+
+	result.Status = CheckPass
+	result.Detail = fmt.Sprintf("version %s", metadata.CurrentVersion())
+	return result
+}
+
+func checkChecksum(metadata *CursorMetadata) DiagnosticResult {
+	result := DiagnosticResult{Name: "Checksum"}
+
+	if metadata == nil || metadata.CurrentChecksum() == "" {
+		result.Status = CheckWarn
+		result.Detail = "no checksum recorded for the installed AppImage"
+		result.Fix = "Run cursor-installer repair to record a checksum"
+		return result
+	}
+
+	checksum, err := fileChecksum(filepath.Join(installDir, appImage))
+	if err != nil {
+		result.Status = CheckFail
+		result.Detail = err.Error()
+		result.Fix = "Reinstall Cursor with cursor-installer --force"
+		return result
+	}
+
+	if checksum != metadata.CurrentChecksum() {
+		result.Status = CheckFail
+		result.Detail = "AppImage does not match the recorded sha256, the file was modified or corrupted"
+		result.Fix = "Reinstall Cursor with cursor-installer --force"
+		return result
+	}
+
+	result.Status = CheckPass
+	result.Detail = "sha256 " + checksum[:12]
+	return result
+}
+
+func checkSandbox() DiagnosticResult {
+	result := DiagnosticResult{Name: "Sandbox"}
+
+	if value, err := readSysctl("/proc/sys/user/max_user_namespaces"); err == nil && value == "0" {
+		result.Status = CheckFail
+		result.Detail = "user namespaces are disabled (user.max_user_namespaces = 0)"
+		result.Fix = "sudo sysctl -w user.max_user_namespaces=15000, or launch Cursor with --no-sandbox"
+		return result
+	}
+
+	if value, err := readSysctl("/proc/sys/kernel/unprivileged_userns_clone"); err == nil && value == "0" {
+		result.Status = CheckFail
+		result.Detail = "unprivileged user namespaces are disabled (kernel.unprivileged_userns_clone = 0)"
+		result.Fix = "sudo sysctl -w kernel.unprivileged_userns_clone=1, or launch Cursor with --no-sandbox"
+		return result
+	}
+
+	if value, err := readSysctl("/proc/sys/kernel/apparmor_restrict_unprivileged_userns"); err == nil && value == "1" {
+		if _, err := os.Stat("/etc/apparmor.d/cursor"); err != nil {
+			result.Status = CheckWarn
+			result.Detail = "AppArmor restricts unprivileged user namespaces and no Cursor profile is installed"
+			result.Fix = "Add an AppArmor profile for /opt/cursor/Cursor.AppImage with the userns permission, or launch Cursor with --no-sandbox"
+			return result
+		}
+	}
+
+	result.Status = CheckPass
+	result.Detail = "user namespaces available for the Chromium sandbox"
+	return result
+}
+
+func readSysctl(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func readDesktopEntry(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	entry := make(map[string]string)
+	inSection := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inSection = line == "[Desktop Entry]"
+			continue
+		}
+		if !inSection || line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok {
+			entry[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+
+	return entry, scanner.Err()
+}
@@ -0,0 +1,231 @@
+package app
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type ExtensionAction string
+
+const (
+	ExtensionInstall ExtensionAction = "install"
+	ExtensionRemove  ExtensionAction = "remove"
+)
+
+type ExtensionChange struct {
+	ID     string
+	Source string
+	Action ExtensionAction
+}
+
+var extensionDirPattern = regexp.MustCompile(`^(.+?)-\d+\.\d+\.\d+.*$`)
+
+func ReadExtensionList(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open extension list: %v", err)
+	}
+	defer file.Close()
+
+	baseDir := filepath.Dir(path)
+	var entries []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasSuffix(strings.ToLower(line), ".vsix") && !filepath.IsAbs(line) {
+			line = filepath.Join(baseDir, line)
+		}
+		entries = append(entries, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read extension list: %v", err)
+	}
+
+	return entries, nil
+}
+
+func (i *Installer) extensionsDir() (string, error) {
+	homeDir, err := i.homeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".cursor", "extensions"), nil
+}
+
+func (i *Installer) InstalledExtensions() (map[string]bool, error) {
+	dir, err := i.extensionsDir()
+	if err != nil {
+		return nil, err
+	}
+	return readExtensionsDir(dir)
+}
+
+// SetPruneExtensions makes PlanExtensions also remove installed extensions
+// that the list does not mention, so the result converges on the list.
+func (i *Installer) SetPruneExtensions(prune bool) {
+	i.pruneExtensions = prune
+}
+
+func (i *Installer) PlanExtensions(entries []string) ([]ExtensionChange, error) {
+	installed, err := i.InstalledExtensions()
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []ExtensionChange
+	listed := make(map[string]bool)
+	for _, entry := range entries {
+		if strings.HasPrefix(entry, "-") {
+			id := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(entry, "-")))
+			listed[id] = true
+			if installed[id] {
+				changes = append(changes, ExtensionChange{ID: id, Source: id, Action: ExtensionRemove})
+			}
+			continue
+		}
+
+		id := entry
+		if strings.HasSuffix(strings.ToLower(entry), ".vsix") {
+			id, err = vsixIdentifier(entry)
+			if err != nil {
+				return nil, err
+			}
+		} else if at := strings.Index(id, "@"); at > 0 {
+			id = id[:at]
+		}
+		id = strings.ToLower(id)
+		listed[id] = true
+
+		if !installed[id] {
+			changes = append(changes, ExtensionChange{ID: id, Source: entry, Action: ExtensionInstall})
+		}
+	}
+
+	if i.pruneExtensions {
+		var unlisted []string
+		for id := range installed {
+			if !listed[id] {
+				unlisted = append(unlisted, id)
+			}
+		}
+		sort.Strings(unlisted)
+		for _, id := range unlisted {
+			changes = append(changes, ExtensionChange{ID: id, Source: id, Action: ExtensionRemove})
+		}
+	}
+
+	return changes, nil
+}
+
+func (i *Installer) ApplyExtensionChange(ctx context.Context, change ExtensionChange) error {
+	flag := "--install-extension"
+	if change.Action == ExtensionRemove {
+		flag = "--uninstall-extension"
+	}
+
+	cmd := commandContext(ctx, cursorExecutable(), flag, change.Source)
+	if err := i.runAsTargetUser(cmd); err != nil {
+		return err
+	}
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if details := strings.TrimSpace(string(output)); details != "" {
+			return fmt.Errorf("failed to %s extension %s: %v: %s", change.Action, change.ID, err, details)
+		}
+		return fmt.Errorf("failed to %s extension %s: %v", change.Action, change.ID, err)
+	}
+	return nil
+}
+
+func vsixIdentifier(path string) (string, error) {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %v", path, err)
+	}
+	defer reader.Close()
+
+	for _, file := range reader.File {
+		if file.Name != "extension/package.json" {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %v", path, err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %v", path, err)
+		}
+
+		var pkg struct {
+			Publisher string `json:"publisher"`
+			Name      string `json:"name"`
+		}
+		if err := json.Unmarshal(data, &pkg); err != nil {
+			return "", fmt.Errorf("failed to parse package.json in %s: %v", path, err)
+		}
+		return pkg.Publisher + "." + pkg.Name, nil
+	}
+
+	return "", fmt.Errorf("no extension manifest found in %s", path)
+}
+
+func readExtensionsDir(dir string) (map[string]bool, error) {
+	installed := make(map[string]bool)
+
+	if data, err := os.ReadFile(filepath.Join(dir, "extensions.json")); err == nil {
+		var manifest []struct {
+			Identifier struct {
+				ID string `json:"id"`
+			} `json:"identifier"`
+		}
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("failed to parse extensions manifest: %v", err)
+		}
+		for _, ext := range manifest {
+			installed[strings.ToLower(ext.Identifier.ID)] = true
+		}
+		return installed, nil
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read extensions manifest: %v", err)
+	}
+
+	dirEntries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return installed, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read extensions directory: %v", err)
+	}
+
+	for _, entry := range dirEntries {
+		if !entry.IsDir() {
+			continue
+		}
+		if matches := extensionDirPattern.FindStringSubmatch(entry.Name()); len(matches) > 1 {
+			installed[strings.ToLower(matches[1])] = true
+		}
+	}
+
+	return installed, nil
+}
+
+func cursorExecutable() string {
+	if _, err := os.Stat(symlinkPath); err == nil {
+		return symlinkPath
+	}
+	return filepath.Join(installDir, appImage)
+}
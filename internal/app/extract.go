@@ -0,0 +1,192 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	LayoutAppImage  = "appimage"
+	LayoutExtracted = "extracted"
+
+	extractedIcon = "usr/share/icons/hicolor/512x512/apps/cursor.png"
+
+	extractStagingPrefix = ".cursor-installer-"
+)
+
+func (i *Installer) SetLayout(layout string) {
+	i.layout = layout
+}
+
+func (i *Installer) Layout() string {
+	if i.layout == "" {
+		return LayoutAppImage
+	}
+	return i.layout
+}
+
+func (i *Installer) applyMetadataLayout(metadata *CursorMetadata) {
+	if i.layout == "" && metadata != nil && metadata.Layout == LayoutExtracted {
+		i.layout = LayoutExtracted
+		if i.version == "" {
+			i.version = metadata.CurrentVersion()
+		}
+	}
+}
+
+func (i *Installer) extracted() bool {
+	return i.layout == LayoutExtracted
+}
+
+func (i *Installer) extractedDir() string {
+	version, _ := i.GetLatestVersion()
+	return filepath.Join(installDir, version)
+}
+
+func (i *Installer) installedBinary() string {
+	if i.extracted() {
+		return filepath.Join(i.extractedDir(), "AppRun")
+	}
+	return filepath.Join(installDir, appImage)
+}
+
+func extractedInstalls() []string {
+	matches, _ := filepath.Glob(filepath.Join(installDir, "*", "AppRun"))
+	dirs := make([]string, 0, len(matches))
+	for _, match := range matches {
+		dir := filepath.Dir(match)
+		if validateVersion(filepath.Base(dir)) == nil {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+func isInstalled() (bool, error) {
+	_, err := os.Stat(filepath.Join(installDir, appImage))
+	if err == nil {
+		return true, nil
+	}
+	if !os.IsNotExist(err) {
+		return false, err
+	}
+	return len(extractedInstalls()) > 0, nil
+}
+
+func (i *Installer) extractToOpt(ctx context.Context) error {
+	version, _ := i.GetLatestVersion()
+	if err := validateVersion(version); err != nil {
+		return fmt.Errorf("refusing to install the extracted tree: %v", err)
+	}
+
+	tempDir, err := os.MkdirTemp("", "cursor-extract")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	source, err := filepath.Abs(appImage)
+	if err != nil {
+		return fmt.Errorf("failed to resolve downloaded AppImage: %v", err)
+	}
+
+	checksum, err := fileChecksum(source)
+	if err != nil {
+		return err
+	}
+	i.checksum = checksum
+
+	cmd := commandContext(ctx, source, "--appimage-extract")
+	cmd.Dir = tempDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to extract AppImage: %v: %s", err, strings.TrimSpace(lastLine(string(output))))
+	}
+
+	extractedRoot := filepath.Join(tempDir, "squashfs-root")
+	if _, err := os.Stat(filepath.Join(extractedRoot, "AppRun")); err != nil {
+		return fmt.Errorf("extracted AppImage has no AppRun: %v", err)
+	}
+
+	// Install into a sibling directory first and rename it into place, so a
+	// running Cursor never sees a half-removed or half-copied tree.
+	targetDir := i.extractedDir()
+	existed := pathExists(targetDir)
+	staging := filepath.Join(installDir, extractStagingPrefix+"new-"+version)
+	previous := filepath.Join(installDir, extractStagingPrefix+"old-"+version)
+	sandbox := pathExists(filepath.Join(extractedRoot, "chrome-sandbox"))
+	if err := runSudo(ctx, "rm", "-rf", staging, previous); err != nil {
+		return fmt.Errorf("failed to remove leftover %s (sudo error): %v", staging, err)
+	}
+	if err := runSudo(ctx, "mv", extractedRoot, staging); err != nil {
+		return fmt.Errorf("failed to copy extracted tree to %s (sudo error): %v", installDir, err)
+	}
+	if err := runSudo(ctx, "chown", "-R", "root:root", staging); err != nil {
+		return fmt.Errorf("failed to set ownership on %s (sudo error): %v", staging, err)
+	}
+	if err := runSudo(ctx, "chmod", "755", staging); err != nil {
+		return fmt.Errorf("failed to set permissions (sudo error): %v", err)
+	}
+	if sandbox {
+		if err := runSudo(ctx, "chmod", "4755", filepath.Join(staging, "chrome-sandbox")); err != nil {
+			return fmt.Errorf("failed to set permissions on chrome-sandbox (sudo error): %v", err)
+		}
+	}
+	if existed {
+		if err := runSudo(ctx, "mv", "-T", targetDir, previous); err != nil {
+			return fmt.Errorf("failed to move %s aside (sudo error): %v", targetDir, err)
+		}
+	}
+	if err := runSudo(ctx, "mv", "-T", staging, targetDir); err != nil {
+		if existed {
+			runSudo(ctx, "mv", "-T", previous, targetDir)
+		}
+		return fmt.Errorf("failed to install extracted tree to %s (sudo error): %v", targetDir, err)
+	}
+	i.recordFile(targetDir, existed)
+
+	if err := os.Remove(appImage); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove downloaded AppImage: %v", err)
+	}
+
+	return i.pruneInactiveLayouts(ctx)
+}
+
+func (i *Installer) pruneInactiveLayouts(ctx context.Context) error {
+	pids, err := i.RunningInstances()
+	if err != nil {
+		return err
+	}
+	if len(pids) > 0 {
+		i.warn(ctx, "Cursor is running, previous installation files were kept")
+		return nil
+	}
+
+	var stale []string
+	for _, dir := range extractedInstalls() {
+		if !i.extracted() || dir != i.extractedDir() {
+			stale = append(stale, dir)
+		}
+	}
+	if i.extracted() {
+		stale = append(stale, filepath.Join(installDir, appImage), filepath.Join(installDir, stagedAppImage))
+	}
+	leftovers, _ := filepath.Glob(filepath.Join(installDir, extractStagingPrefix+"old-*"))
+	stale = append(stale, leftovers...)
+	if len(stale) == 0 {
+		return nil
+	}
+
+	if err := runSudo(ctx, append([]string{"rm", "-rf"}, stale...)...); err != nil {
+		return fmt.Errorf("failed to remove previous installation (sudo error): %v", err)
+	}
+
+	return nil
+}
+
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return lines[len(lines)-1]
+}
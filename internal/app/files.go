@@ -1,106 +1,207 @@
 package app
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strings"
 )
 
-func (i *Installer) ensureInstallDir() error {
-	cmd := exec.Command("sudo", "-S", "mkdir", "-p", installDir)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+var downloadFilename = regexp.MustCompile(`cursor-(.+?)(?:x86_64)?\.AppImage`)
+
+func (i *Installer) ensureInstallDir(ctx context.Context) error {
+	if err := runSudo(ctx, "mkdir", "-p", installDir); err != nil {
 		return fmt.Errorf("failed to create install directory (sudo error): %v", err)
 	}
 
-	cmd = exec.Command("sudo", "-S", "chmod", "755", installDir)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+	if err := runSudo(ctx, "chmod", "755", installDir); err != nil {
 		return fmt.Errorf("failed to set permissions on install directory (sudo error): %v", err)
 	}
 
 	return nil
 }
 
-func (i *Installer) DownloadCursor() error {
-	resp, err := http.Get(cursorURL)
+func (i *Installer) DownloadCursor(ctx context.Context) error {
+	return i.download(ctx, appImage)
+}
+
+func (i *Installer) download(ctx context.Context, target string) error {
+	// Forget the version seeded from the previous installation's metadata, so
+	// a download that reports none is not recorded under the old version.
+	i.version = ""
+	source := i.versionURL()
+	if i.targetVersion != "" {
+		cached, err := i.cachedDownload(i.targetVersion)
+		if err == nil && cached != nil {
+			return i.useCached(ctx, cached, target)
+		}
+		if i.mirror == "" {
+			latest, err := i.LatestVersion(ctx)
+			if err != nil {
+				return fmt.Errorf("Cursor %s is not in the download cache: %v", i.targetVersion, err)
+			}
+			if latest != i.targetVersion {
+				return fmt.Errorf("Cursor %s is not in the download cache and the download endpoint only serves the latest version, use --mirror to fetch it from a mirror", i.targetVersion)
+			}
+			source = i.downloadURL()
+		}
+	}
+
+	resp, err := i.httpGet(ctx, source)
 	if err != nil {
 		return fmt.Errorf("failed to download Cursor: %v", err)
 	}
 	defer resp.Body.Close()
 
-	contentDisposition := resp.Header.Get("Content-Disposition")
-	var originalFilename string
-	if strings.Contains(contentDisposition, "filename=") {
-		originalFilename = strings.Split(contentDisposition, "filename=")[1]
-		originalFilename = strings.Trim(originalFilename, "\"")
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download Cursor: %s returned %s", source, resp.Status)
 	}
 
-	if originalFilename != "" {
-		re, err := regexp.Compile(`cursor-(.+?)(?:x86_64)?\.AppImage`)
-		if err != nil {
-			return fmt.Errorf("failed to compile regex: %v", err)
-		}
-		matches := re.FindStringSubmatch(originalFilename)
-		if len(matches) > 1 {
-			i.version = matches[1]
+	version, err := versionFromResponse(resp)
+	if err != nil {
+		return fmt.Errorf("failed to download Cursor: %v", err)
+	}
+	if i.targetVersion != "" && version != i.targetVersion {
+		return fmt.Errorf("failed to download Cursor %s: %s returned version %q", i.targetVersion, source, version)
+	}
+	if version != "" {
+		i.version = version
+		cached, err := i.cachedDownload(version)
+		if err == nil && cached != nil {
+			return i.useCached(ctx, cached, target)
 		}
 	}
 
-	out, err := os.Create(appImage)
+	var out *os.File
+	if target == "" {
+		out, err = os.CreateTemp("", "cursor-download-*.AppImage")
+		if err == nil {
+			defer os.Remove(out.Name())
+		}
+	} else {
+		out, err = os.Create(target)
+		if err == nil {
+			i.downloadPath, _ = filepath.Abs(target)
+		}
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create file: %v", err)
 	}
 	defer out.Close()
 
-	if _, err := io.Copy(out, resp.Body); err != nil {
+	hash := sha256.New()
+	written, err := io.Copy(io.MultiWriter(out, hash), resp.Body)
+	i.report.Downloaded += written
+	if err != nil {
+		out.Close()
+		os.Remove(out.Name())
+		return fmt.Errorf("failed to save download: %v", err)
+	}
+	if err := out.Close(); err != nil {
 		return fmt.Errorf("failed to save download: %v", err)
 	}
 
+	if version == "" {
+		i.warn(ctx, "The download did not report a version, so it was not cached")
+		return nil
+	}
+	err = i.storeInCache(version, out.Name(), hex.EncodeToString(hash.Sum(nil)))
+	if target == "" {
+		return err
+	}
+	if err != nil {
+		i.warn(ctx, fmt.Sprintf("The download could not be cached: %v", err))
+	}
 	return nil
 }
 
-func (i *Installer) MakeExecutable() error {
-	cmd := exec.Command("sudo", "-S", "chmod", "+x", appImage)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+func versionFromResponse(resp *http.Response) (string, error) {
+	contentDisposition := resp.Header.Get("Content-Disposition")
+	if !strings.Contains(contentDisposition, "filename=") {
+		return "", nil
+	}
+	originalFilename := strings.Trim(strings.Split(contentDisposition, "filename=")[1], "\"")
+
+	matches := downloadFilename.FindStringSubmatch(originalFilename)
+	if len(matches) < 2 {
+		return "", nil
+	}
+	version, _, _ := strings.Cut(matches[1], "-build-")
+	version = strings.TrimRight(version, "-_")
+	if err := validateVersion(version); err != nil {
+		return "", fmt.Errorf("server reported an %v", err)
+	}
+	return version, nil
+}
+
+func (i *Installer) useCached(ctx context.Context, cached *CacheEntry, target string) error {
+	logger.InfoContext(ctx, "using cached download", "version", cached.Version, "path", cached.Path)
+	i.version = cached.Version
+	i.report.FromCache = true
+	if target == "" {
+		return nil
+	}
+	i.downloadPath, _ = filepath.Abs(target)
+	return copyFile(cached.Path, target)
+}
+
+func (i *Installer) MakeExecutable(ctx context.Context) error {
+	if err := runSudo(ctx, "chmod", "+x", appImage); err != nil {
 		return fmt.Errorf("failed to make file executable (sudo error): %v", err)
 	}
 	return nil
 }
 
-func (i *Installer) MoveToOpt() error {
-	if err := i.ensureInstallDir(); err != nil {
+func (i *Installer) MoveToOpt(ctx context.Context) error {
+	if err := i.ensureInstallDir(ctx); err != nil {
 		return err
 	}
 
-	targetPath := filepath.Join(installDir, appImage)
-	cmd := exec.Command("sudo", "-S", "mv", appImage, targetPath)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+	if i.extracted() {
+		return i.extractToOpt(ctx)
+	}
+
+	targetPath := i.appImagePath()
+	existed := pathExists(targetPath)
+	if err := runSudo(ctx, "mv", appImage, targetPath); err != nil {
 		return fmt.Errorf("failed to move file to %s (sudo error): %v", installDir, err)
 	}
+	i.recordFile(targetPath, existed)
 
-	cmd = exec.Command("sudo", "-S", "chmod", "755", targetPath)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+	if err := runSudo(ctx, "chmod", "755", targetPath); err != nil {
 		return fmt.Errorf("failed to set permissions (sudo error): %v", err)
 	}
 
-	return nil
+	if i.staging {
+		return i.installLauncher(ctx)
+	}
+
+	if err := runSudo(ctx, "rm", "-f", filepath.Join(installDir, stagedAppImage)); err != nil {
+		return fmt.Errorf("failed to remove stale staged update (sudo error): %v", err)
+	}
+
+	return i.pruneInactiveLayouts(ctx)
+}
+
+func (i *Installer) Cleanup() []string {
+	var removed []string
+	if i.downloadPath != "" && os.Remove(i.downloadPath) == nil {
+		removed = append(removed, i.downloadPath)
+	}
+
+	if dir, err := i.CacheDir(); err == nil {
+		partials, _ := filepath.Glob(filepath.Join(dir, "*.partial"))
+		for _, partial := range partials {
+			if os.Remove(partial) == nil {
+				removed = append(removed, partial)
+			}
+		}
+	}
+	return removed
 }
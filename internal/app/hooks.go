@@ -0,0 +1,120 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type HookPoint string
+
+const (
+	HookPreDownload HookPoint = "pre-download"
+	HookPreInstall  HookPoint = "pre-install"
+	HookPostInstall HookPoint = "post-install"
+	HookPostUpdate  HookPoint = "post-update"
+)
+
+func (i *Installer) SetHooks(hooks map[HookPoint]string) {
+	i.hooks = hooks
+}
+
+func (i *Installer) HasHook(point HookPoint) bool {
+	return i.hooks[point] != ""
+}
+
+func (i *Installer) HookPath(point HookPoint) string {
+	return i.hooks[point]
+}
+
+func (i *Installer) IsUpdate() bool {
+	return i.previousVersion != "" && i.version != "" && i.previousVersion != i.version
+}
+
+func (i *Installer) RunHook(ctx context.Context, point HookPoint) error {
+	path := i.hooks[point]
+	if path == "" {
+		return nil
+	}
+	if point == HookPostUpdate && !i.IsUpdate() {
+		return nil
+	}
+
+	scripts, err := hookScripts(path)
+	if err != nil {
+		return fmt.Errorf("%s hook: %v", point, err)
+	}
+
+	env := append(os.Environ(), i.hookEnv(point)...)
+	for _, script := range scripts {
+		var output bytes.Buffer
+		cmd := commandContext(ctx, script)
+		cmd.Env = env
+		if w := outputFrom(ctx); w != nil {
+			cmd.Stdout = io.MultiWriter(&output, w)
+		} else {
+			cmd.Stdout = &output
+		}
+		cmd.Stderr = cmd.Stdout
+		err := cmd.Run()
+		logger.Debug("hook finished", "hook", string(point), "script", script, "output", output.String())
+		if err != nil {
+			if tail := strings.TrimSpace(output.String()); tail != "" {
+				return fmt.Errorf("%s hook %s failed: %v: %s", point, script, err, lastLine(tail))
+			}
+			return fmt.Errorf("%s hook %s failed: %v", point, script, err)
+		}
+	}
+	return nil
+}
+
+func hookScripts(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var scripts []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+			continue
+		}
+		scripts = append(scripts, filepath.Join(path, entry.Name()))
+	}
+	sort.Strings(scripts)
+	return scripts, nil
+}
+
+func (i *Installer) hookEnv(point HookPoint) []string {
+	download, _ := filepath.Abs(appImage)
+	return []string{
+		"CURSOR_HOOK=" + string(point),
+		"CURSOR_OLD_VERSION=" + i.previousVersion,
+		"CURSOR_NEW_VERSION=" + i.version,
+		"CURSOR_LAYOUT=" + i.Layout(),
+		"CURSOR_INSTALL_DIR=" + installDir,
+		"CURSOR_BINARY=" + i.installedBinary(),
+		"CURSOR_LAUNCH_PATH=" + i.launchPath(),
+		"CURSOR_DOWNLOAD_PATH=" + download,
+		"CURSOR_SYMLINK=" + symlinkPath,
+		"CURSOR_DESKTOP_ENTRY=" + desktopEntryPath,
+		"CURSOR_METADATA=" + metadataPath,
+	}
+}
@@ -0,0 +1,126 @@
+package app
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+const defaultConnectTimeout = 30 * time.Second
+
+type HTTPOptions struct {
+	DownloadURL    string
+	Timeout        time.Duration
+	ConnectTimeout time.Duration
+	UserAgent      string
+	Proxy          string
+	NoProxy        string
+	CABundle       string
+	Headers        map[string]string
+}
+
+func (i *Installer) SetHTTPOptions(opts HTTPOptions) {
+	i.httpOptions = opts
+	i.client = nil
+}
+
+func newHTTPClient(opts HTTPOptions) (*http.Client, error) {
+	proxyConfig := httpproxy.FromEnvironment()
+	if opts.Proxy != "" {
+		proxyConfig.HTTPProxy = opts.Proxy
+		proxyConfig.HTTPSProxy = opts.Proxy
+	}
+	if opts.NoProxy != "" {
+		proxyConfig.NoProxy = opts.NoProxy
+	}
+	proxyFunc := proxyConfig.ProxyFunc()
+
+	connectTimeout := opts.ConnectTimeout
+	if connectTimeout <= 0 {
+		connectTimeout = defaultConnectTimeout
+	}
+
+	tlsConfig := &tls.Config{}
+	if opts.CABundle != "" {
+		pem, err := os.ReadFile(opts.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %v", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", opts.CABundle)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	transport := &http.Transport{
+		Proxy: func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		},
+		DialContext:         (&net.Dialer{Timeout: connectTimeout, KeepAlive: 30 * time.Second}).DialContext,
+		TLSHandshakeTimeout: connectTimeout,
+		TLSClientConfig:     tlsConfig,
+		ForceAttemptHTTP2:   true,
+	}
+
+	return &http.Client{Transport: transport, Timeout: opts.Timeout}, nil
+}
+
+// Custom headers usually carry credentials for the download server or mirror,
+// so they are not sent to the changelog or release hosts.
+func (i *Installer) downloadHost(target *url.URL) bool {
+	download, err := url.Parse(i.downloadURL())
+	return err == nil && strings.EqualFold(download.Host, target.Host)
+}
+
+func (i *Installer) httpGet(ctx context.Context, target string) (*http.Response, error) {
+	return i.httpRequest(ctx, http.MethodGet, target)
+}
+
+func (i *Installer) httpRequest(ctx context.Context, method, target string) (*http.Response, error) {
+	if i.client == nil {
+		client, err := newHTTPClient(i.httpOptions)
+		if err != nil {
+			return nil, err
+		}
+		i.client = client
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	userAgent := i.httpOptions.UserAgent
+	if userAgent == "" {
+		userAgent = "cursor-installer/" + InstallerVersion
+	}
+	req.Header.Set("User-Agent", userAgent)
+	if i.downloadHost(req.URL) {
+		for key, value := range i.httpOptions.Headers {
+			req.Header.Set(key, value)
+		}
+	}
+
+	started := time.Now()
+	logger.DebugContext(ctx, "http request", "method", req.Method, "url", req.URL.Redacted(), "headers", i.redactHeaders(req.Header))
+	resp, err := i.client.Do(req)
+	if err != nil {
+		logger.WarnContext(ctx, "http request failed", "url", req.URL.Redacted(), "duration", time.Since(started), "error", err.Error())
+		return nil, err
+	}
+	logger.DebugContext(ctx, "http response", "url", req.URL.Redacted(), "status", resp.Status, "headers", i.redactHeaders(resp.Header), "duration", time.Since(started))
+	return resp, nil
+}
@@ -0,0 +1,99 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+const lockFile = "cursor-installer.lock"
+
+type Lock struct {
+	file *os.File
+}
+
+type LockedError struct {
+	PID  int
+	Path string
+}
+
+func (e *LockedError) Error() string {
+	if e.PID == 0 {
+		return fmt.Sprintf("another cursor-installer is running (lock held on %s)", e.Path)
+	}
+	return fmt.Sprintf("another cursor-installer (pid %d) is running", e.PID)
+}
+
+func LockPath() string {
+	for _, dir := range []string{"/run/lock", "/var/lock"} {
+		if unix.Access(dir, unix.W_OK) == nil {
+			return filepath.Join(dir, lockFile)
+		}
+	}
+	return filepath.Join(os.TempDir(), lockFile)
+}
+
+func AcquireLock(ctx context.Context, wait bool) (*Lock, error) {
+	path := LockPath()
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if errors.Is(err, os.ErrPermission) {
+		file, err = os.Open(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file %s: %v", path, err)
+	}
+	if err := os.Chmod(path, 0644); err != nil && !errors.Is(err, os.ErrPermission) {
+		file.Close()
+		return nil, fmt.Errorf("failed to set permissions on lock file %s: %v", path, err)
+	}
+
+	for {
+		err := unix.Flock(int(file.Fd()), unix.LOCK_EX|unix.LOCK_NB)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, unix.EWOULDBLOCK) {
+			file.Close()
+			return nil, fmt.Errorf("failed to lock %s: %v", path, err)
+		}
+		if !wait {
+			file.Close()
+			return nil, &LockedError{PID: lockHolder(path), Path: path}
+		}
+
+		select {
+		case <-ctx.Done():
+			file.Close()
+			return nil, ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+
+	if err := file.Truncate(0); err == nil {
+		file.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+	return &Lock{file: file}, nil
+}
+
+func (l *Lock) Release() error {
+	defer l.file.Close()
+	l.file.Truncate(0)
+	return unix.Flock(int(l.file.Fd()), unix.LOCK_UN)
+}
+
+func lockHolder(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	return pid
+}
@@ -1,21 +1,52 @@
 package app
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"time"
 )
 
-const metadataPath = "/opt/cursor/metadata.json"
-
 type CursorMetadata struct {
 	Version        string    `json:"version"`
 	InstallDate    time.Time `json:"install_date"`
 	LastUpdateDate time.Time `json:"last_update_date"`
 	InstallPath    string    `json:"install_path"`
+	LaunchPath     string    `json:"launch_path,omitempty"`
+	IconPath       string    `json:"icon_path,omitempty"`
+	DesktopEntry   string    `json:"desktop_entry,omitempty"`
+	SymlinkPath    string    `json:"symlink_path,omitempty"`
+	StagedVersion  string    `json:"staged_version,omitempty"`
+	Checksum       string    `json:"sha256,omitempty"`
+	StagedChecksum string    `json:"staged_sha256,omitempty"`
+	Layout         string    `json:"layout,omitempty"`
+}
+
+func (m *CursorMetadata) stagedApplied() bool {
+	if m.StagedVersion == "" {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(installDir, stagedAppImage))
+	return os.IsNotExist(err)
+}
+
+func (m *CursorMetadata) CurrentVersion() string {
+	if m.stagedApplied() {
+		return m.StagedVersion
+	}
+	return m.Version
+}
+
+func (m *CursorMetadata) CurrentChecksum() string {
+	if m.stagedApplied() {
+		return m.StagedChecksum
+	}
+	return m.Checksum
 }
 
 func (i *Installer) GetLatestVersion() (string, error) {
@@ -25,7 +56,7 @@ func (i *Installer) GetLatestVersion() (string, error) {
 	return "unknown", nil
 }
 
-func (i *Installer) readMetadata() (*CursorMetadata, error) {
+func (i *Installer) readMetadata(ctx context.Context) (*CursorMetadata, error) {
 	if _, err := os.Stat(metadataPath); os.IsNotExist(err) {
 		return nil, nil
 	}
@@ -38,12 +69,8 @@ func (i *Installer) readMetadata() (*CursorMetadata, error) {
 	tmpFile.Close()
 	defer os.Remove(tmpPath)
 
-	cmd := exec.Command("sudo", "-S", "sh", "-c", fmt.Sprintf("cp %s %s && chmod 644 %s", metadataPath, tmpPath, tmpPath))
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("failed to copy and set permissions on metadata file (sudo error): %v", err)
+	if err := runSudo(ctx, "cp", metadataPath, tmpPath); err != nil {
+		return nil, fmt.Errorf("failed to copy metadata file (sudo error): %v", err)
 	}
 
 	data, err := os.ReadFile(tmpPath)
@@ -59,7 +86,9 @@ func (i *Installer) readMetadata() (*CursorMetadata, error) {
 	return &metadata, nil
 }
 
-func (i *Installer) writeMetadata(metadata *CursorMetadata) error {
+func (i *Installer) writeMetadata(ctx context.Context, metadata *CursorMetadata) error {
+	existed := pathExists(metadataPath)
+
 	tmpFile, err := os.CreateTemp("", "cursor-metadata-*.json")
 	if err != nil {
 		return fmt.Errorf("failed to create temporary metadata file: %v", err)
@@ -77,19 +106,30 @@ func (i *Installer) writeMetadata(metadata *CursorMetadata) error {
 	}
 	tmpFile.Close()
 
-	cmd := exec.Command("sudo", "-S", "sh", "-c", fmt.Sprintf("mv %s %s && chmod 644 %s", tmpPath, metadataPath, metadataPath))
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+	if err := runSudo(ctx, "install", "-m", "644", tmpPath, metadataPath); err != nil {
 		return fmt.Errorf("failed to install and set permissions on metadata file (sudo error): %v", err)
 	}
+	i.recordFile(metadataPath, existed)
 
 	return nil
 }
 
-func (i *Installer) UpdateMetadata() error {
-	if err := i.ensureInstallDir(); err != nil {
+func fileChecksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to checksum %s: %v", path, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (i *Installer) UpdateMetadata(ctx context.Context) error {
+	if err := i.ensureInstallDir(ctx); err != nil {
 		return err
 	}
 
@@ -101,19 +141,47 @@ func (i *Installer) UpdateMetadata() error {
 	metadata := &CursorMetadata{
 		Version:        latestVersion,
 		InstallPath:    filepath.Join(installDir, appImage),
+		LaunchPath:     i.launchPath(),
+		IconPath:       filepath.Join(installDir, iconFile),
+		DesktopEntry:   desktopEntryPath,
+		SymlinkPath:    symlinkPath,
+		Layout:         i.Layout(),
 		LastUpdateDate: time.Now(),
 	}
+	if i.extracted() {
+		metadata.InstallPath = i.extractedDir()
+	}
 
-	existingMetadata, err := i.readMetadata()
+	existingMetadata, err := i.readMetadata(ctx)
 	if err != nil {
 		return err
 	}
 
 	if existingMetadata != nil {
 		metadata.InstallDate = existingMetadata.InstallDate
+		if i.staging {
+			metadata.Version = existingMetadata.CurrentVersion()
+			metadata.StagedVersion = latestVersion
+			metadata.Checksum = existingMetadata.CurrentChecksum()
+			stagedChecksum, err := fileChecksum(filepath.Join(installDir, stagedAppImage))
+			if err != nil {
+				return err
+			}
+			metadata.StagedChecksum = stagedChecksum
+		}
 	} else {
 		metadata.InstallDate = time.Now()
 	}
 
-	return i.writeMetadata(metadata)
+	if i.checksum != "" {
+		metadata.Checksum = i.checksum
+	} else if !i.staging && !i.extracted() {
+		checksum, err := fileChecksum(metadata.InstallPath)
+		if err != nil {
+			return err
+		}
+		metadata.Checksum = checksum
+	}
+
+	return i.writeMetadata(ctx, metadata)
 }
@@ -0,0 +1,183 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+)
+
+type migrationSource struct {
+	configDir     string
+	extensionsDir string
+}
+
+var migrationSources = map[string]migrationSource{
+	"vscode":   {configDir: ".config/Code/User", extensionsDir: ".vscode/extensions"},
+	"vscodium": {configDir: ".config/VSCodium/User", extensionsDir: ".vscode-oss/extensions"},
+	"code-oss": {configDir: ".config/Code - OSS/User", extensionsDir: ".vscode-oss/extensions"},
+}
+
+type MigrationPlan struct {
+	Source          string
+	SourceDir       string
+	Settings        map[string]interface{}
+	SkippedSettings []string
+	Keybindings     []interface{}
+	Snippets        []string
+	Extensions      []ExtensionChange
+}
+
+func MigrationSources() []string {
+	names := make([]string, 0, len(migrationSources))
+	for name := range migrationSources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (i *Installer) PlanMigration(from string, overwrite bool) (*MigrationPlan, error) {
+	source, ok := migrationSources[from]
+	if !ok {
+		return nil, fmt.Errorf("unknown migration source %q", from)
+	}
+
+	homeDir, err := i.homeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	sourceDir := filepath.Join(homeDir, source.configDir)
+	if _, err := os.Stat(sourceDir); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no %s configuration found at %s", from, sourceDir)
+		}
+		return nil, fmt.Errorf("failed to check %s configuration: %v", from, err)
+	}
+
+	plan := &MigrationPlan{
+		Source:    from,
+		SourceDir: sourceDir,
+		Settings:  make(map[string]interface{}),
+	}
+
+	sourceSettings, err := readSettingsFile(filepath.Join(sourceDir, "settings.json"))
+	if err != nil {
+		return nil, err
+	}
+	currentSettings, err := i.ReadSettings()
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range sourceSettings {
+		current, exists := currentSettings[key]
+		if exists && reflect.DeepEqual(current, value) {
+			continue
+		}
+		if exists && !overwrite {
+			plan.SkippedSettings = append(plan.SkippedSettings, key)
+			continue
+		}
+		plan.Settings[key] = value
+	}
+	sort.Strings(plan.SkippedSettings)
+
+	sourceKeybindings, err := readKeybindingsFile(filepath.Join(sourceDir, "keybindings.json"))
+	if err != nil {
+		return nil, err
+	}
+	currentKeybindings, err := i.ReadKeybindings()
+	if err != nil {
+		return nil, err
+	}
+	plan.Keybindings = missingKeybindings(currentKeybindings, sourceKeybindings)
+
+	cursorDir, err := i.cursorUserDir()
+	if err != nil {
+		return nil, err
+	}
+	snippets, err := os.ReadDir(filepath.Join(sourceDir, "snippets"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read snippets: %v", err)
+	}
+	for _, snippet := range snippets {
+		if snippet.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(cursorDir, "snippets", snippet.Name())); os.IsNotExist(err) || overwrite {
+			plan.Snippets = append(plan.Snippets, snippet.Name())
+		}
+	}
+
+	sourceExtensions, err := readExtensionsDir(filepath.Join(homeDir, source.extensionsDir))
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(sourceExtensions))
+	for id := range sourceExtensions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	plan.Extensions, err = i.PlanExtensions(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+func (p *MigrationPlan) SettingKeys() []string {
+	keys := make([]string, 0, len(p.Settings))
+	for key := range p.Settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (p *MigrationPlan) IsEmpty() bool {
+	return len(p.Settings) == 0 && len(p.Keybindings) == 0 && len(p.Snippets) == 0 && len(p.Extensions) == 0
+}
+
+func (i *Installer) ImportSettings(plan *MigrationPlan) error {
+	if len(plan.Settings) == 0 {
+		return nil
+	}
+	return i.MergeSettings(plan.Settings)
+}
+
+func (i *Installer) ImportKeybindings(plan *MigrationPlan) error {
+	if len(plan.Keybindings) == 0 {
+		return nil
+	}
+	return i.MergeKeybindings(plan.Keybindings)
+}
+
+func (i *Installer) ImportSnippets(plan *MigrationPlan) error {
+	if len(plan.Snippets) == 0 {
+		return nil
+	}
+
+	cursorDir, err := i.cursorUserDir()
+	if err != nil {
+		return err
+	}
+	targetDir := filepath.Join(cursorDir, "snippets")
+	if err := i.mkdirAllOwned(targetDir); err != nil {
+		return fmt.Errorf("failed to create snippets directory: %v", err)
+	}
+
+	for _, name := range plan.Snippets {
+		data, err := os.ReadFile(filepath.Join(plan.SourceDir, "snippets", name))
+		if err != nil {
+			return fmt.Errorf("failed to read snippet %s: %v", name, err)
+		}
+		if err := i.writeUserFile(filepath.Join(targetDir, name), data, 0644); err != nil {
+			return fmt.Errorf("failed to write snippet %s: %v", name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const mirrorPath = "/linux/appImage/x64"
+
+func (i *Installer) SetMirror(mirror string) {
+	i.mirror = mirror
+}
+
+func (i *Installer) downloadURL() string {
+	if i.mirror == "" {
+		if i.httpOptions.DownloadURL != "" {
+			return i.httpOptions.DownloadURL
+		}
+		return cursorURL
+	}
+
+	mirror := strings.TrimSuffix(i.mirror, "/")
+	if parsed, err := url.Parse(mirror); err == nil && parsed.Path == "" {
+		return mirror + mirrorPath
+	}
+	return mirror
+}
+
+func (i *Installer) latestCached(version string) (*CacheEntry, error) {
+	entries, err := i.CacheEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *CacheEntry
+	for idx, entry := range entries {
+		if version != "" && entry.Version != version {
+			continue
+		}
+		if latest == nil || compareVersions(entry.Version, latest.Version) > 0 {
+			latest = &entries[idx]
+		}
+	}
+	return latest, nil
+}
+
+func (i *Installer) refreshMirror(ctx context.Context) error {
+	lock, err := AcquireLock(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+	return i.download(ctx, "")
+}
+
+func (i *Installer) MirrorHandler(log io.Writer) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(mirrorPath, func(w http.ResponseWriter, r *http.Request) {
+		client, _, _ := net.SplitHostPort(r.RemoteAddr)
+
+		version := r.URL.Query().Get("version")
+		if version != "" {
+			if err := validateVersion(version); err != nil {
+				fmt.Fprintf(log, "%s %s %s %d %v\n", time.Now().Format(time.RFC3339), client, r.URL, http.StatusBadRequest, err)
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		entry, err := i.latestCached(version)
+		if err != nil {
+			fmt.Fprintf(log, "%s %s %s %d %v\n", time.Now().Format(time.RFC3339), client, r.URL, http.StatusInternalServerError, err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if entry == nil {
+			fmt.Fprintf(log, "%s %s %s %d no cached AppImage\n", time.Now().Format(time.RFC3339), client, r.URL, http.StatusNotFound)
+			http.Error(w, "no cached Cursor AppImage", http.StatusNotFound)
+			return
+		}
+
+		file, err := os.Open(entry.Path)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer file.Close()
+
+		filename := fmt.Sprintf("cursor-%sx86_64.AppImage", entry.Version)
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		fmt.Fprintf(log, "%s %s %s %d %s\n", time.Now().Format(time.RFC3339), client, r.URL, http.StatusOK, entry.Version)
+		http.ServeContent(w, r, filename, entry.Modified, file)
+	})
+	mux.HandleFunc(versionsPath, func(w http.ResponseWriter, r *http.Request) {
+		client, _, _ := net.SplitHostPort(r.RemoteAddr)
+
+		versions, err := i.cachedVersions()
+		if err != nil {
+			fmt.Fprintf(log, "%s %s %s %d %v\n", time.Now().Format(time.RFC3339), client, r.URL, http.StatusInternalServerError, err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		fmt.Fprintf(log, "%s %s %s %d %d versions\n", time.Now().Format(time.RFC3339), client, r.URL, http.StatusOK, len(versions))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(versions)
+	})
+	mux.HandleFunc(changelogPath, func(w http.ResponseWriter, r *http.Request) {
+		client, _, _ := net.SplitHostPort(r.RemoteAddr)
+
+		data, err := i.readChangelog(r.Context())
+		if err != nil {
+			fmt.Fprintf(log, "%s %s %s %d %v\n", time.Now().Format(time.RFC3339), client, r.URL, http.StatusBadGateway, err)
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		fmt.Fprintf(log, "%s %s %s %d\n", time.Now().Format(time.RFC3339), client, r.URL, http.StatusOK)
+		w.Write(data)
+	})
+	return mux
+}
+
+// ServeMirror does not hold the installer lock while serving, that would block
+// every install on the machine for as long as the mirror runs. Each refresh
+// takes the lock instead, so it cannot race cache prune or cache clear.
+func (i *Installer) ServeMirror(ctx context.Context, addr string, refresh time.Duration, log io.Writer) error {
+	if refresh > 0 {
+		go func() {
+			for {
+				if err := i.refreshMirror(ctx); err != nil {
+					fmt.Fprintf(log, "%s refresh failed: %v\n", time.Now().Format(time.RFC3339), err)
+				} else {
+					fmt.Fprintf(log, "%s refreshed from %s, latest version %s\n", time.Now().Format(time.RFC3339), i.downloadURL(), i.version)
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(refresh):
+				}
+			}
+		}()
+	}
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           i.MirrorHandler(log),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cancelGracePeriod)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
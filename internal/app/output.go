@@ -0,0 +1,67 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+type outputKey struct{}
+
+func WithOutput(ctx context.Context, w io.Writer) context.Context {
+	return context.WithValue(ctx, outputKey{}, w)
+}
+
+func outputFrom(ctx context.Context) io.Writer {
+	w, _ := ctx.Value(outputKey{}).(io.Writer)
+	return w
+}
+
+func stdout(ctx context.Context) io.Writer {
+	if w := outputFrom(ctx); w != nil {
+		return w
+	}
+	return os.Stdout
+}
+
+func stderr(ctx context.Context) io.Writer {
+	if w := outputFrom(ctx); w != nil {
+		return w
+	}
+	return os.Stderr
+}
+
+type outputHandler struct {
+	slog.Handler
+}
+
+func (h outputHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return outputFrom(ctx) != nil || h.Handler.Enabled(ctx, level)
+}
+
+func (h outputHandler) Handle(ctx context.Context, record slog.Record) error {
+	if w := outputFrom(ctx); w != nil {
+		var line strings.Builder
+		line.WriteString(record.Message)
+		record.Attrs(func(attr slog.Attr) bool {
+			fmt.Fprintf(&line, " %s=%v", attr.Key, attr.Value)
+			return true
+		})
+		fmt.Fprintf(w, "[%s] %s\n", strings.ToLower(record.Level.String()), line.String())
+	}
+	if !h.Handler.Enabled(ctx, record.Level) {
+		return nil
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h outputHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return outputHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h outputHandler) WithGroup(name string) slog.Handler {
+	return outputHandler{h.Handler.WithGroup(name)}
+}
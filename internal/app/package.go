@@ -0,0 +1,338 @@
+package app
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"debug/elf"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	packageName        = "cursor"
+	packageRelease     = "1"
+	packageSummary     = "The AI code editor"
+	packageDescription = "Cursor editor packaged from the official AppImage by cursor-installer."
+	packageURL         = "https://cursor.com"
+	packageInstallDir  = "/opt/cursor"
+	packageSymlink     = "/usr/bin/cursor"
+	packageDesktop     = "/usr/share/applications/cursor.desktop"
+	packageIcon        = "/usr/share/icons/hicolor/512x512/apps/cursor.png"
+)
+
+type PackageFormat string
+
+const (
+	PackageDeb PackageFormat = "deb"
+	PackageRPM PackageFormat = "rpm"
+	PackageTar PackageFormat = "tar"
+)
+
+func ParsePackageFormat(format string) (PackageFormat, error) {
+	switch PackageFormat(format) {
+	case PackageDeb, PackageRPM, PackageTar:
+		return PackageFormat(format), nil
+	}
+	return "", fmt.Errorf("unknown package format %q, expected deb, rpm or tar", format)
+}
+
+type PackageSource struct {
+	AppImage   string
+	Version    string
+	Arch       string
+	icon       []byte
+	downloaded bool
+}
+
+type packageFile struct {
+	path   string
+	mode   os.FileMode
+	link   string
+	data   []byte
+	source string
+	size   int64
+}
+
+func (f packageFile) open() (io.ReadCloser, error) {
+	if f.source != "" {
+		return os.Open(f.source)
+	}
+	if f.link != "" {
+		return io.NopCloser(strings.NewReader(f.link)), nil
+	}
+	return io.NopCloser(bytes.NewReader(f.data)), nil
+}
+
+func (i *Installer) InspectAppImage(ctx context.Context, source string) (*PackageSource, error) {
+	pkg := &PackageSource{AppImage: source}
+	if source == "" {
+		pkg.AppImage = appImage
+		pkg.downloaded = true
+	}
+
+	absPath, err := filepath.Abs(pkg.AppImage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %v", pkg.AppImage, err)
+	}
+	pkg.AppImage = absPath
+
+	if _, err := os.Stat(absPath); err != nil {
+		return nil, fmt.Errorf("failed to read AppImage: %v", err)
+	}
+
+	binary, err := elf.Open(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not an AppImage: %v", absPath, err)
+	}
+	switch binary.Machine {
+	case elf.EM_X86_64:
+		pkg.Arch = "amd64"
+	case elf.EM_AARCH64:
+		pkg.Arch = "arm64"
+	default:
+		binary.Close()
+		return nil, fmt.Errorf("unsupported AppImage architecture %s", binary.Machine)
+	}
+	binary.Close()
+
+	tempDir, err := os.MkdirTemp("", "cursor-package")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Run an executable copy so the user's --appimage file is left untouched.
+	executable := filepath.Join(tempDir, appImage)
+	if err := copyFile(absPath, executable); err != nil {
+		return nil, err
+	}
+
+	cmd := commandContext(ctx, executable, "--appimage-extract")
+	cmd.Dir = tempDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("failed to extract AppImage: %v: %s", err, lastLine(string(output)))
+	}
+	extractedRoot := filepath.Join(tempDir, "squashfs-root")
+
+	pkg.icon, err = os.ReadFile(filepath.Join(extractedRoot, extractedIcon))
+	if err != nil {
+		return nil, fmt.Errorf("icon not found in extracted contents: %v", err)
+	}
+
+	pkg.Version = appImageVersion(extractedRoot)
+	if pkg.Version == "" && pkg.downloaded {
+		pkg.Version = i.version
+	}
+	if pkg.Version == "" {
+		re := regexp.MustCompile(`(?i)cursor-(.+?)(?:-?x86_64|-?aarch64)?\.AppImage`)
+		if matches := re.FindStringSubmatch(filepath.Base(absPath)); len(matches) > 1 {
+			pkg.Version = matches[1]
+		}
+	}
+	if pkg.Version == "" {
+		return nil, fmt.Errorf("could not determine the Cursor version of %s", absPath)
+	}
+	if err := validateVersion(pkg.Version); err != nil {
+		return nil, fmt.Errorf("cannot package %s: %v", absPath, err)
+	}
+
+	return pkg, nil
+}
+
+func appImageVersion(extractedRoot string) string {
+	for _, pattern := range []string{"usr/share/*/resources/app/package.json", "resources/app/package.json"} {
+		matches, _ := filepath.Glob(filepath.Join(extractedRoot, pattern))
+		for _, match := range matches {
+			data, err := os.ReadFile(match)
+			if err != nil {
+				continue
+			}
+			var manifest struct {
+				Version string `json:"version"`
+			}
+			if json.Unmarshal(data, &manifest) == nil && manifest.Version != "" {
+				return manifest.Version
+			}
+		}
+	}
+	return ""
+}
+
+func (p *PackageSource) Close() error {
+	if !p.downloaded {
+		return nil
+	}
+	if err := os.Remove(p.AppImage); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove downloaded AppImage: %v", err)
+	}
+	return nil
+}
+
+func (p *PackageSource) Build(format PackageFormat, outputDir, maintainer string) (string, error) {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %v", err)
+	}
+
+	info, err := os.Stat(p.AppImage)
+	if err != nil {
+		return "", fmt.Errorf("failed to read AppImage: %v", err)
+	}
+
+	// Packages always use the standard locations, whatever paths this machine
+	// is configured to install to.
+	appImagePath := path.Join(packageInstallDir, appImage)
+	iconPath := path.Join(packageInstallDir, iconFile)
+	files := []packageFile{
+		{path: packageInstallDir, mode: os.ModeDir | 0755},
+		{path: appImagePath, mode: 0755, source: p.AppImage, size: info.Size()},
+		{path: iconPath, mode: 0644, data: p.icon, size: int64(len(p.icon))},
+		{path: packageIcon, mode: 0644, data: p.icon, size: int64(len(p.icon))},
+		{path: packageSymlink, mode: os.ModeSymlink | 0777, link: appImagePath, size: int64(len(appImagePath))},
+	}
+	entry := desktopEntry(appImagePath, iconPath)
+	files = append(files, packageFile{path: packageDesktop, mode: 0644, data: []byte(entry), size: int64(len(entry))})
+	sort.Slice(files, func(a, b int) bool { return files[a].path < files[b].path })
+
+	var target string
+	switch format {
+	case PackageDeb:
+		target = filepath.Join(outputDir, fmt.Sprintf("%s_%s-%s_%s.deb", packageName, p.Version, packageRelease, p.Arch))
+		err = writeDeb(target, p, files, maintainer)
+	case PackageRPM:
+		target = filepath.Join(outputDir, fmt.Sprintf("%s-%s-%s.%s.rpm", packageName, rpmVersion(p.Version), packageRelease, rpmArch(p.Arch)))
+		err = writeRPM(target, p, files, maintainer)
+	default:
+		target = filepath.Join(outputDir, fmt.Sprintf("%s-%s-%s.tar.gz", packageName, p.Version, p.Arch))
+		err = writeTarball(target, files)
+	}
+	if err != nil {
+		os.Remove(target)
+		return "", err
+	}
+
+	return target, nil
+}
+
+func writeTarball(target string, files []packageFile) error {
+	out, err := os.Create(target)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", target, err)
+	}
+	defer out.Close()
+
+	if err := writeTarGz(out, "", files, time.Now()); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+func writeTarGz(w io.Writer, prefix string, files []packageFile, modTime time.Time) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	written := make(map[string]bool)
+	for _, file := range files {
+		var parents []string
+		for dir := path.Dir(file.path); dir != "/"; dir = path.Dir(dir) {
+			parents = append([]string{dir}, parents...)
+		}
+		for _, dir := range parents {
+			if written[dir] {
+				continue
+			}
+			written[dir] = true
+			if err := writeTarEntry(tw, prefix, packageFile{path: dir, mode: os.ModeDir | 0755}, modTime); err != nil {
+				return err
+			}
+		}
+		if written[file.path] {
+			continue
+		}
+		written[file.path] = true
+		if err := writeTarEntry(tw, prefix, file, modTime); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish archive: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to compress archive: %v", err)
+	}
+	return nil
+}
+
+func writeTarEntry(tw *tar.Writer, prefix string, file packageFile, modTime time.Time) error {
+	header := &tar.Header{
+		Name:    prefix + strings.TrimPrefix(file.path, "/"),
+		Mode:    int64(file.mode.Perm()),
+		ModTime: modTime,
+		Uname:   "root",
+		Gname:   "root",
+		Format:  tar.FormatGNU,
+	}
+	switch {
+	case file.mode.IsDir():
+		header.Typeflag = tar.TypeDir
+		header.Name += "/"
+	case file.mode&os.ModeSymlink != 0:
+		header.Typeflag = tar.TypeSymlink
+		header.Linkname = file.link
+	default:
+		header.Typeflag = tar.TypeReg
+		header.Size = file.size
+	}
+
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s to archive: %v", file.path, err)
+	}
+	if header.Typeflag != tar.TypeReg {
+		return nil
+	}
+
+	reader, err := file.open()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", file.path, err)
+	}
+	defer reader.Close()
+	if _, err := io.Copy(tw, reader); err != nil {
+		return fmt.Errorf("failed to write %s to archive: %v", file.path, err)
+	}
+	return nil
+}
+
+func fileDigest(file packageFile, hash hash.Hash) (string, error) {
+	reader, err := file.open()
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %v", file.path, err)
+	}
+	defer reader.Close()
+
+	if _, err := io.Copy(hash, reader); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %v", file.path, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func installedSize(files []packageFile) int64 {
+	var size int64
+	for _, file := range files {
+		if file.mode.IsRegular() {
+			size += file.size
+		}
+	}
+	return size
+}
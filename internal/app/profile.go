@@ -0,0 +1,96 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Profile struct {
+	ExportedAt  time.Time              `json:"exported_at"`
+	Settings    map[string]interface{} `json:"settings,omitempty"`
+	Keybindings []interface{}          `json:"keybindings,omitempty"`
+	Extensions  []string               `json:"extensions,omitempty"`
+}
+
+func (i *Installer) ExportProfile(cursorOnly bool) (*Profile, error) {
+	settings, err := i.ReadSettings()
+	if err != nil {
+		return nil, err
+	}
+	if cursorOnly {
+		for key := range settings {
+			if !strings.HasPrefix(key, "cursor.") {
+				delete(settings, key)
+			}
+		}
+	}
+
+	keybindings, err := i.ReadKeybindings()
+	if err != nil {
+		return nil, err
+	}
+
+	installed, err := i.InstalledExtensions()
+	if err != nil {
+		return nil, err
+	}
+	extensions := make([]string, 0, len(installed))
+	for id := range installed {
+		extensions = append(extensions, id)
+	}
+	sort.Strings(extensions)
+
+	profile := &Profile{
+		ExportedAt:  time.Now(),
+		Settings:    settings,
+		Keybindings: keybindings,
+		Extensions:  extensions,
+	}
+
+	return profile, nil
+}
+
+func ReadProfile(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile: %v", err)
+	}
+
+	var profile Profile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("failed to parse profile: %v", err)
+	}
+
+	return &profile, nil
+}
+
+func WriteProfile(path string, profile *Profile) error {
+	data, err := json.MarshalIndent(profile, "", "\t")
+	if err != nil {
+		return fmt.Errorf("failed to marshal profile: %v", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write profile: %v", err)
+	}
+
+	return nil
+}
+
+func (i *Installer) ApplyProfile(profile *Profile) error {
+	if len(profile.Settings) > 0 {
+		if err := i.MergeSettings(profile.Settings); err != nil {
+			return err
+		}
+	}
+	if len(profile.Keybindings) > 0 {
+		if err := i.MergeKeybindings(profile.Keybindings); err != nil {
+			return err
+		}
+	}
+	return nil
+}
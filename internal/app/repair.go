@@ -0,0 +1,207 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type RepairAction struct {
+	Name   string
+	Reason string
+	Run    func(ctx context.Context) error
+}
+
+func (i *Installer) PlanRepair(ctx context.Context) ([]RepairAction, error) {
+	metadata, err := i.readMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	i.applyMetadataLayout(metadata)
+
+	manifest := CursorMetadata{
+		LaunchPath:   i.launchPath(),
+		IconPath:     filepath.Join(installDir, iconFile),
+		DesktopEntry: desktopEntryPath,
+		SymlinkPath:  symlinkPath,
+	}
+	if metadata != nil {
+		i.version = metadata.CurrentVersion()
+		if metadata.LaunchPath != "" {
+			manifest.LaunchPath = metadata.LaunchPath
+		}
+	}
+
+	binaryPath := i.installedBinary()
+	info, err := os.Stat(binaryPath)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%s is missing and cannot be repaired without downloading, run cursor-installer --force", binaryPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check installation: %v", err)
+	}
+
+	var actions []RepairAction
+
+	if !i.extracted() && metadata != nil && metadata.CurrentChecksum() != "" {
+		checksum, err := fileChecksum(binaryPath)
+		if err != nil {
+			return nil, err
+		}
+		if checksum != metadata.CurrentChecksum() {
+			actions = append(actions, RepairAction{
+				Name:   "Reinstall",
+				Reason: fmt.Sprintf("%s does not match its recorded checksum", binaryPath),
+				Run:    i.Reinstall,
+			})
+		}
+	}
+
+	if info.Mode().Perm() != 0755 {
+		actions = append(actions, RepairAction{
+			Name:   "Fix Permissions",
+			Reason: fmt.Sprintf("%s has mode %s instead of 0755", binaryPath, info.Mode().Perm()),
+			Run:    i.FixPermissions,
+		})
+	}
+
+	if _, err := os.Stat(manifest.IconPath); err != nil {
+		actions = append(actions, RepairAction{
+			Name:   "Extract Icon",
+			Reason: fmt.Sprintf("%s is missing", manifest.IconPath),
+			Run:    i.ExtractIcon,
+		})
+	}
+
+	entry, err := readDesktopEntry(manifest.DesktopEntry)
+	switch {
+	case err != nil:
+		actions = append(actions, RepairAction{
+			Name:   "Create Desktop Entry",
+			Reason: fmt.Sprintf("%s is missing", manifest.DesktopEntry),
+			Run:    i.CreateDesktopEntry,
+		})
+	case desktopEntryExec(entry) != manifest.LaunchPath || entry["Icon"] != manifest.IconPath:
+		actions = append(actions, RepairAction{
+			Name:   "Create Desktop Entry",
+			Reason: fmt.Sprintf("%s does not point at %s", manifest.DesktopEntry, manifest.LaunchPath),
+			Run:    i.CreateDesktopEntry,
+		})
+	}
+
+	if target, err := os.Readlink(manifest.SymlinkPath); err != nil {
+		actions = append(actions, RepairAction{
+			Name:   "Create Symlink",
+			Reason: fmt.Sprintf("%s is missing", manifest.SymlinkPath),
+			Run:    i.CreateSymlink,
+		})
+	} else if target != manifest.LaunchPath {
+		actions = append(actions, RepairAction{
+			Name:   "Create Symlink",
+			Reason: fmt.Sprintf("%s points to %s instead of %s", manifest.SymlinkPath, target, manifest.LaunchPath),
+			Run:    i.CreateSymlink,
+		})
+	}
+
+	if metadata == nil || metadata.LaunchPath == "" || metadata.IconPath == "" || metadata.DesktopEntry == "" || metadata.SymlinkPath == "" || (metadata.CurrentChecksum() == "" && !i.extracted()) {
+		actions = append(actions, RepairAction{
+			Name:   "Update Metadata",
+			Reason: "installation manifest is missing or incomplete",
+			Run:    i.RepairMetadata,
+		})
+	}
+
+	return actions, nil
+}
+
+func (i *Installer) FixPermissions(ctx context.Context) error {
+	if err := runSudo(ctx, "chmod", "755", installDir, i.installedBinary()); err != nil {
+		return fmt.Errorf("failed to set permissions (sudo error): %v", err)
+	}
+	return nil
+}
+
+// Reinstall downloads Cursor again to replace an installed AppImage that no
+// longer matches its recorded checksum.
+func (i *Installer) Reinstall(ctx context.Context) error {
+	for _, step := range []func(context.Context) error{
+		i.DownloadCursor,
+		i.MakeExecutable,
+		i.HandleRunningInstances,
+		i.MoveToOpt,
+		i.UpdateMetadata,
+	} {
+		if err := step(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RepairMetadata fills in the fields missing from the manifest. Recorded
+// checksums, dates and staged state are kept, so a modified AppImage is never
+// recorded as the good one.
+func (i *Installer) RepairMetadata(ctx context.Context) error {
+	metadata, err := i.readMetadata(ctx)
+	if err != nil {
+		return err
+	}
+	if metadata == nil {
+		return i.UpdateMetadata(ctx)
+	}
+
+	if metadata.Version == "" {
+		metadata.Version, _ = i.GetLatestVersion()
+	}
+	if metadata.InstallPath == "" {
+		metadata.InstallPath = filepath.Join(installDir, appImage)
+		if i.extracted() {
+			metadata.InstallPath = i.extractedDir()
+		}
+	}
+	if metadata.LaunchPath == "" {
+		metadata.LaunchPath = i.launchPath()
+	}
+	if metadata.IconPath == "" {
+		metadata.IconPath = filepath.Join(installDir, iconFile)
+	}
+	if metadata.DesktopEntry == "" {
+		metadata.DesktopEntry = desktopEntryPath
+	}
+	if metadata.SymlinkPath == "" {
+		metadata.SymlinkPath = symlinkPath
+	}
+	if metadata.Layout == "" {
+		metadata.Layout = i.Layout()
+	}
+
+	if !i.extracted() {
+		current := &metadata.Checksum
+		if metadata.stagedApplied() {
+			current = &metadata.StagedChecksum
+		}
+		if *current == "" {
+			if *current, err = fileChecksum(filepath.Join(installDir, appImage)); err != nil {
+				return err
+			}
+		}
+		if metadata.StagedVersion != "" && metadata.StagedChecksum == "" && !metadata.stagedApplied() {
+			if metadata.StagedChecksum, err = fileChecksum(filepath.Join(installDir, stagedAppImage)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return i.writeMetadata(ctx, metadata)
+}
+
+func desktopEntryExec(entry map[string]string) string {
+	fields := strings.Fields(entry["Exec"])
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"sort"
+)
+
+type FileChange struct {
+	Path   string `json:"path"`
+	Action string `json:"action"`
+}
+
+type SettingsChange struct {
+	Path string   `json:"path"`
+	Keys []string `json:"keys"`
+}
+
+type Report struct {
+	PreviousVersion string           `json:"previous_version,omitempty"`
+	Version         string           `json:"version,omitempty"`
+	Downloaded      int64            `json:"bytes_downloaded"`
+	FromCache       bool             `json:"from_cache"`
+	Files           []FileChange     `json:"files,omitempty"`
+	Settings        []SettingsChange `json:"settings,omitempty"`
+	Warnings        []string         `json:"warnings,omitempty"`
+}
+
+func (i *Installer) Report() Report {
+	report := *i.report
+	report.PreviousVersion = i.previousVersion
+	if i.version != "" {
+		report.Version = i.version
+	}
+	return report
+}
+
+func pathExists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+func (i *Installer) recordFile(path string, existed bool) {
+	action := "created"
+	if existed {
+		action = "modified"
+	}
+	for idx, change := range i.report.Files {
+		if change.Path == path {
+			i.report.Files[idx].Action = action
+			return
+		}
+	}
+	i.report.Files = append(i.report.Files, FileChange{Path: path, Action: action})
+}
+
+func changedKeys(existing, values map[string]interface{}) []string {
+	var keys []string
+	for key, value := range values {
+		if previous, ok := existing[key]; !ok || !reflect.DeepEqual(previous, value) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (i *Installer) recordSettings(path string, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	i.report.Settings = append(i.report.Settings, SettingsChange{Path: path, Keys: keys})
+}
+
+func (i *Installer) warn(ctx context.Context, message string) {
+	logger.WarnContext(ctx, message)
+	i.report.Warnings = append(i.report.Warnings, message)
+}
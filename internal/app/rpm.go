@@ -0,0 +1,425 @@
+package app
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	rpmTypeInt16       = 3
+	rpmTypeInt32       = 4
+	rpmTypeString      = 6
+	rpmTypeBin         = 7
+	rpmTypeStringArray = 8
+	rpmTypeI18NString  = 9
+
+	rpmTagSignatures     = 62
+	rpmTagImmutable      = 63
+	rpmTagI18NTable      = 100
+	rpmSigTagSHA1        = 269
+	rpmSigTagSHA256      = 273
+	rpmSigTagSize        = 1000
+	rpmSigTagMD5         = 1004
+	rpmSigTagPayloadSize = 1007
+
+	rpmSenseLess    = 0x02
+	rpmSenseEqual   = 0x08
+	rpmSenseRPMLib  = 0x1000000
+	rpmDigestSHA256 = 8
+)
+
+const (
+	rpmTagName        = 1000
+	rpmTagVersion     = 1001
+	rpmTagRelease     = 1002
+	rpmTagSummary     = 1004
+	rpmTagDescription = 1005
+	rpmTagBuildTime   = 1006
+	rpmTagBuildHost   = 1007
+	rpmTagSize        = 1009
+	rpmTagLicense     = 1014
+	rpmTagPackager    = 1015
+	rpmTagGroup       = 1016
+	rpmTagURL         = 1020
+	rpmTagOS          = 1021
+	rpmTagArch        = 1022
+)
+
+const (
+	rpmTagFileSizes         = 1028
+	rpmTagFileModes         = 1030
+	rpmTagFileRdevs         = 1033
+	rpmTagFileMtimes        = 1034
+	rpmTagFileDigests       = 1035
+	rpmTagFileLinkTos       = 1036
+	rpmTagFileFlags         = 1037
+	rpmTagFileUsername      = 1039
+	rpmTagFileGroupname     = 1040
+	rpmTagSourceRPM         = 1044
+	rpmTagFileVerifyFlags   = 1045
+	rpmTagProvideName       = 1047
+	rpmTagRequireFlags      = 1048
+	rpmTagRequireName       = 1049
+	rpmTagRequireVersion    = 1050
+	rpmTagFileDevices       = 1095
+	rpmTagFileInodes        = 1096
+	rpmTagFileLangs         = 1097
+	rpmTagProvideFlags      = 1112
+	rpmTagProvideVersion    = 1113
+	rpmTagDirIndexes        = 1116
+	rpmTagBasenames         = 1117
+	rpmTagDirnames          = 1118
+	rpmTagPayloadFormat     = 1124
+	rpmTagPayloadCompressor = 1125
+	rpmTagPayloadFlags      = 1126
+	rpmTagFileDigestAlgo    = 5011
+)
+
+type rpmEntry struct {
+	kind  uint32
+	count uint32
+	data  []byte
+}
+
+type rpmHeader struct {
+	region  uint32
+	entries map[uint32]rpmEntry
+}
+
+func newRPMHeader(region uint32) *rpmHeader {
+	return &rpmHeader{region: region, entries: make(map[uint32]rpmEntry)}
+}
+
+func (h *rpmHeader) addString(tag uint32, value string) {
+	h.entries[tag] = rpmEntry{kind: rpmTypeString, count: 1, data: append([]byte(value), 0)}
+}
+
+func (h *rpmHeader) addI18NString(tag uint32, value string) {
+	h.entries[tag] = rpmEntry{kind: rpmTypeI18NString, count: 1, data: append([]byte(value), 0)}
+}
+
+func (h *rpmHeader) addStrings(tag uint32, values []string) {
+	var data []byte
+	for _, value := range values {
+		data = append(append(data, value...), 0)
+	}
+	h.entries[tag] = rpmEntry{kind: rpmTypeStringArray, count: uint32(len(values)), data: data}
+}
+
+func (h *rpmHeader) addInt32(tag uint32, values ...uint32) {
+	data := make([]byte, 4*len(values))
+	for idx, value := range values {
+		binary.BigEndian.PutUint32(data[4*idx:], value)
+	}
+	h.entries[tag] = rpmEntry{kind: rpmTypeInt32, count: uint32(len(values)), data: data}
+}
+
+func (h *rpmHeader) addInt16(tag uint32, values ...uint16) {
+	data := make([]byte, 2*len(values))
+	for idx, value := range values {
+		binary.BigEndian.PutUint16(data[2*idx:], value)
+	}
+	h.entries[tag] = rpmEntry{kind: rpmTypeInt16, count: uint32(len(values)), data: data}
+}
+
+func (h *rpmHeader) addBin(tag uint32, value []byte) {
+	h.entries[tag] = rpmEntry{kind: rpmTypeBin, count: uint32(len(value)), data: value}
+}
+
+func (h *rpmHeader) bytes() []byte {
+	tags := make([]uint32, 0, len(h.entries))
+	for tag := range h.entries {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(a, b int) bool { return tags[a] < tags[b] })
+
+	var index, store bytes.Buffer
+	for _, tag := range tags {
+		entry := h.entries[tag]
+		alignment := map[uint32]int{rpmTypeInt16: 2, rpmTypeInt32: 4}[entry.kind]
+		for alignment > 0 && store.Len()%alignment != 0 {
+			store.WriteByte(0)
+		}
+		binary.Write(&index, binary.BigEndian, []uint32{tag, entry.kind, uint32(store.Len()), entry.count})
+		store.Write(entry.data)
+	}
+
+	regionOffset := store.Len()
+	binary.Write(&store, binary.BigEndian, []int32{int32(h.region), rpmTypeBin, int32(-16 * (len(tags) + 1)), 16})
+
+	var out bytes.Buffer
+	out.Write([]byte{0x8e, 0xad, 0xe8, 0x01, 0, 0, 0, 0})
+	binary.Write(&out, binary.BigEndian, []uint32{uint32(len(tags) + 1), uint32(store.Len())})
+	binary.Write(&out, binary.BigEndian, []uint32{h.region, rpmTypeBin, uint32(regionOffset), 16})
+	out.Write(index.Bytes())
+	out.Write(store.Bytes())
+	return out.Bytes()
+}
+
+func rpmVersion(version string) string {
+	return strings.ReplaceAll(version, "-", "_")
+}
+
+func rpmArch(arch string) string {
+	if arch == "arm64" {
+		return "aarch64"
+	}
+	return "x86_64"
+}
+
+func writeRPM(target string, p *PackageSource, files []packageFile, maintainer string) error {
+	now := uint32(time.Now().Unix())
+	version := rpmVersion(p.Version)
+
+	payload, err := os.CreateTemp("", "cursor-rpm-payload-*.cpio.gz")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	defer os.Remove(payload.Name())
+	defer payload.Close()
+
+	header := newRPMHeader(rpmTagImmutable)
+	header.addStrings(rpmTagI18NTable, []string{"C"})
+	header.addString(rpmTagName, packageName)
+	header.addString(rpmTagVersion, version)
+	header.addString(rpmTagRelease, packageRelease)
+	header.addI18NString(rpmTagSummary, packageSummary)
+	header.addI18NString(rpmTagDescription, packageDescription)
+	header.addInt32(rpmTagBuildTime, now)
+	if hostname, err := os.Hostname(); err == nil {
+		header.addString(rpmTagBuildHost, hostname)
+	}
+	header.addInt32(rpmTagSize, uint32(installedSize(files)))
+	header.addString(rpmTagLicense, "Proprietary")
+	header.addString(rpmTagPackager, maintainer)
+	header.addI18NString(rpmTagGroup, "Development/Tools")
+	header.addString(rpmTagURL, packageURL)
+	header.addString(rpmTagOS, "linux")
+	header.addString(rpmTagArch, rpmArch(p.Arch))
+	header.addString(rpmTagSourceRPM, fmt.Sprintf("%s-%s-%s.src.rpm", packageName, version, packageRelease))
+
+	var (
+		sizes, mtimes, flags, verifyFlags, devices, inodes, dirIndexes []uint32
+		modes, rdevs                                                   []uint16
+		digests, links, users, groups, langs, basenames, dirnames      []string
+	)
+	dirIndex := make(map[string]uint32)
+	for idx, file := range files {
+		mode := uint16(file.mode.Perm())
+		digest := ""
+		switch {
+		case file.mode.IsDir():
+			mode |= 0040000
+		case file.mode&os.ModeSymlink != 0:
+			mode |= 0120000
+		default:
+			mode |= 0100000
+			sum, err := fileDigest(file, sha256.New())
+			if err != nil {
+				return err
+			}
+			digest = sum
+		}
+
+		dir := path.Dir(file.path) + "/"
+		if _, ok := dirIndex[dir]; !ok {
+			dirIndex[dir] = uint32(len(dirnames))
+			dirnames = append(dirnames, dir)
+		}
+
+		sizes = append(sizes, uint32(file.size))
+		mtimes = append(mtimes, now)
+		flags = append(flags, 0)
+		verifyFlags = append(verifyFlags, 0xffffffff)
+		devices = append(devices, 1)
+		inodes = append(inodes, uint32(idx+1))
+		dirIndexes = append(dirIndexes, dirIndex[dir])
+		modes = append(modes, mode)
+		rdevs = append(rdevs, 0)
+		digests = append(digests, digest)
+		links = append(links, file.link)
+		users = append(users, "root")
+		groups = append(groups, "root")
+		langs = append(langs, "")
+		basenames = append(basenames, path.Base(file.path))
+	}
+
+	header.addInt32(rpmTagFileSizes, sizes...)
+	header.addInt16(rpmTagFileModes, modes...)
+	header.addInt16(rpmTagFileRdevs, rdevs...)
+	header.addInt32(rpmTagFileMtimes, mtimes...)
+	header.addStrings(rpmTagFileDigests, digests)
+	header.addStrings(rpmTagFileLinkTos, links)
+	header.addInt32(rpmTagFileFlags, flags...)
+	header.addStrings(rpmTagFileUsername, users)
+	header.addStrings(rpmTagFileGroupname, groups)
+	header.addInt32(rpmTagFileVerifyFlags, verifyFlags...)
+	header.addInt32(rpmTagFileDevices, devices...)
+	header.addInt32(rpmTagFileInodes, inodes...)
+	header.addStrings(rpmTagFileLangs, langs)
+	header.addInt32(rpmTagDirIndexes, dirIndexes...)
+	header.addStrings(rpmTagBasenames, basenames)
+	header.addStrings(rpmTagDirnames, dirnames)
+	header.addInt32(rpmTagFileDigestAlgo, rpmDigestSHA256)
+
+	header.addStrings(rpmTagProvideName, []string{packageName})
+	header.addInt32(rpmTagProvideFlags, rpmSenseEqual)
+	header.addStrings(rpmTagProvideVersion, []string{version + "-" + packageRelease})
+	header.addStrings(rpmTagRequireName, []string{"fuse-libs", "rpmlib(CompressedFileNames)", "rpmlib(FileDigests)", "rpmlib(PayloadFilesHavePrefix)"})
+	header.addInt32(rpmTagRequireFlags, 0, rpmSenseLess|rpmSenseEqual|rpmSenseRPMLib, rpmSenseLess|rpmSenseEqual|rpmSenseRPMLib, rpmSenseLess|rpmSenseEqual|rpmSenseRPMLib)
+	header.addStrings(rpmTagRequireVersion, []string{"", "3.0.4-1", "4.6.0-1", "4.0-1"})
+
+	header.addString(rpmTagPayloadFormat, "cpio")
+	header.addString(rpmTagPayloadCompressor, "gzip")
+	header.addString(rpmTagPayloadFlags, "9")
+
+	payloadSize, err := writeCPIOPayload(payload, files, now)
+	if err != nil {
+		return err
+	}
+
+	headerBytes := header.bytes()
+	digest := md5.New()
+	digest.Write(headerBytes)
+	if _, err := payload.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read payload: %v", err)
+	}
+	compressedSize, err := io.Copy(digest, payload)
+	if err != nil {
+		return fmt.Errorf("failed to read payload: %v", err)
+	}
+	sha1Sum := sha1.Sum(headerBytes)
+	sha256Sum := sha256.Sum256(headerBytes)
+
+	signature := newRPMHeader(rpmTagSignatures)
+	signature.addString(rpmSigTagSHA1, hex.EncodeToString(sha1Sum[:]))
+	signature.addString(rpmSigTagSHA256, hex.EncodeToString(sha256Sum[:]))
+	signature.addInt32(rpmSigTagSize, uint32(int64(len(headerBytes))+compressedSize))
+	signature.addBin(rpmSigTagMD5, digest.Sum(nil))
+	signature.addInt32(rpmSigTagPayloadSize, uint32(payloadSize))
+	signatureBytes := signature.bytes()
+	if padding := len(signatureBytes) % 8; padding != 0 {
+		signatureBytes = append(signatureBytes, make([]byte, 8-padding)...)
+	}
+
+	out, err := os.Create(target)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", target, err)
+	}
+	defer out.Close()
+
+	lead := make([]byte, 96)
+	copy(lead, []byte{0xed, 0xab, 0xee, 0xdb, 3, 0})
+	binary.BigEndian.PutUint16(lead[8:], 1)
+	copy(lead[10:75], fmt.Sprintf("%s-%s-%s", packageName, version, packageRelease))
+	binary.BigEndian.PutUint16(lead[76:], 1)
+	binary.BigEndian.PutUint16(lead[78:], 5)
+
+	for _, part := range [][]byte{lead, signatureBytes, headerBytes} {
+		if _, err := out.Write(part); err != nil {
+			return fmt.Errorf("failed to write %s: %v", target, err)
+		}
+	}
+	if _, err := payload.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read payload: %v", err)
+	}
+	if _, err := io.Copy(out, payload); err != nil {
+		return fmt.Errorf("failed to write %s: %v", target, err)
+	}
+
+	return out.Close()
+}
+
+func writeCPIOPayload(w io.Writer, files []packageFile, modTime uint32) (int64, error) {
+	gz, err := gzip.NewWriterLevel(w, gzip.BestCompression)
+	if err != nil {
+		return 0, fmt.Errorf("failed to compress payload: %v", err)
+	}
+	counter := &countingWriter{w: gz}
+
+	for idx, file := range files {
+		mode := uint32(file.mode.Perm())
+		size := uint32(file.size)
+		switch {
+		case file.mode.IsDir():
+			mode |= 0040000
+			size = 0
+		case file.mode&os.ModeSymlink != 0:
+			mode |= 0120000
+		default:
+			mode |= 0100000
+		}
+
+		if err := writeCPIOHeader(counter, "."+file.path, uint32(idx+1), mode, size, modTime); err != nil {
+			return 0, err
+		}
+		if !file.mode.IsDir() {
+			reader, err := file.open()
+			if err != nil {
+				return 0, fmt.Errorf("failed to read %s: %v", file.path, err)
+			}
+			_, err = io.Copy(counter, reader)
+			reader.Close()
+			if err != nil {
+				return 0, fmt.Errorf("failed to write %s to payload: %v", file.path, err)
+			}
+			if err := cpioPad(counter); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	if err := writeCPIOHeader(counter, "TRAILER!!!", 0, 0, 0, 0); err != nil {
+		return 0, err
+	}
+	if err := gz.Close(); err != nil {
+		return 0, fmt.Errorf("failed to compress payload: %v", err)
+	}
+
+	return counter.n, nil
+}
+
+func writeCPIOHeader(w *countingWriter, name string, inode, mode, size, modTime uint32) error {
+	nlink := 1
+	if mode&0040000 != 0 {
+		nlink = 2
+	}
+	header := fmt.Sprintf("070701%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%s\x00",
+		inode, mode, 0, 0, nlink, modTime, size, 0, 0, 0, 0, len(name)+1, 0, name)
+	if _, err := io.WriteString(w, header); err != nil {
+		return fmt.Errorf("failed to write payload: %v", err)
+	}
+	return cpioPad(w)
+}
+
+func cpioPad(w *countingWriter) error {
+	if padding := w.n % 4; padding != 0 {
+		if _, err := w.Write(make([]byte, 4-padding)); err != nil {
+			return fmt.Errorf("failed to write payload: %v", err)
+		}
+	}
+	return nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,204 @@
+package app
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type parsedRPMHeader struct {
+	raw  []byte
+	tags map[uint32][]byte
+	kind map[uint32]uint32
+	size map[uint32]uint32
+}
+
+func parseRPMHeader(t *testing.T, data []byte) (*parsedRPMHeader, []byte) {
+	t.Helper()
+	if len(data) < 16 || !bytes.Equal(data[:4], []byte{0x8e, 0xad, 0xe8, 0x01}) {
+		t.Fatalf("bad header magic % x", data[:min(len(data), 4)])
+	}
+	count := binary.BigEndian.Uint32(data[8:])
+	storeSize := binary.BigEndian.Uint32(data[12:])
+	index := data[16 : 16+16*count]
+	store := data[16+16*count : 16+16*count+storeSize]
+
+	header := &parsedRPMHeader{
+		raw:  data[:16+16*count+storeSize],
+		tags: make(map[uint32][]byte),
+		kind: make(map[uint32]uint32),
+		size: make(map[uint32]uint32),
+	}
+	for idx := uint32(0); idx < count; idx++ {
+		entry := index[16*idx:]
+		tag := binary.BigEndian.Uint32(entry)
+		header.kind[tag] = binary.BigEndian.Uint32(entry[4:])
+		header.tags[tag] = store[binary.BigEndian.Uint32(entry[8:]):]
+		header.size[tag] = binary.BigEndian.Uint32(entry[12:])
+	}
+	return header, data[len(header.raw):]
+}
+
+func (h *parsedRPMHeader) strings(tag uint32) []string {
+	values := make([]string, 0, h.size[tag])
+	data := h.tags[tag]
+	for idx := uint32(0); idx < h.size[tag]; idx++ {
+		end := bytes.IndexByte(data, 0)
+		values = append(values, string(data[:end]))
+		data = data[end+1:]
+	}
+	return values
+}
+
+func (h *parsedRPMHeader) int32s(tag uint32) []uint32 {
+	values := make([]uint32, h.size[tag])
+	for idx := range values {
+		values[idx] = binary.BigEndian.Uint32(h.tags[tag][4*idx:])
+	}
+	return values
+}
+
+func readCPIO(t *testing.T, payload []byte) map[string]int64 {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		t.Fatalf("payload is not gzip: %v", err)
+	}
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("failed to decompress payload: %v", err)
+	}
+
+	field := func(header []byte, idx int) int64 {
+		value, err := strconv.ParseInt(string(header[6+8*idx:14+8*idx]), 16, 64)
+		if err != nil {
+			t.Fatalf("bad cpio header field %d: %v", idx, err)
+		}
+		return value
+	}
+	align := func(offset int64) int64 { return (offset + 3) &^ 3 }
+
+	entries := make(map[string]int64)
+	var offset int64
+	for {
+		header := data[offset : offset+110]
+		if string(header[:6]) != "070701" {
+			t.Fatalf("bad cpio magic %q at %d", header[:6], offset)
+		}
+		size, nameSize := field(header, 6), field(header, 11)
+		name := string(data[offset+110 : offset+110+nameSize-1])
+		offset = align(offset + 110 + nameSize)
+		if name == "TRAILER!!!" {
+			return entries
+		}
+		entries[name] = size
+		offset = align(offset + size)
+	}
+}
+
+func TestWriteRPMRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "Cursor.AppImage")
+	if err := os.WriteFile(source, []byte("not really an AppImage"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	pkg := &PackageSource{AppImage: source, Version: "1.3.1", Arch: "amd64", icon: []byte("png")}
+	target, err := pkg.Build(PackageRPM, dir, "Test <test@example.com>")
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	data, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(data[:4], []byte{0xed, 0xab, 0xee, 0xdb}) {
+		t.Fatalf("bad lead magic % x", data[:4])
+	}
+	signature, rest := parseRPMHeader(t, data[96:])
+	if padding := len(signature.raw) % 8; padding != 0 {
+		rest = rest[8-padding:]
+	}
+	header, payload := parseRPMHeader(t, rest)
+
+	if got := string(bytes.TrimRight(signature.tags[rpmSigTagSHA256][:64], "\x00")); got != hex.EncodeToString(sha256Sum(header.raw)) {
+		t.Errorf("signature sha256 %s does not match the header", got)
+	}
+	digest := md5.New()
+	digest.Write(header.raw)
+	digest.Write(payload)
+	if !bytes.Equal(signature.tags[rpmSigTagMD5][:16], digest.Sum(nil)) {
+		t.Errorf("signature md5 does not match header and payload")
+	}
+	if size := signature.int32s(rpmSigTagSize)[0]; size != uint32(len(header.raw)+len(payload)) {
+		t.Errorf("signature size %d, want %d", size, len(header.raw)+len(payload))
+	}
+
+	if name := header.strings(rpmTagName); name[0] != packageName {
+		t.Errorf("name %q, want %q", name[0], packageName)
+	}
+	if version := header.strings(rpmTagVersion); version[0] != "1.3.1" {
+		t.Errorf("version %q, want 1.3.1", version[0])
+	}
+	requires := header.strings(rpmTagRequireName)
+	if len(requires) != len(header.int32s(rpmTagRequireFlags)) || len(requires) != len(header.strings(rpmTagRequireVersion)) {
+		t.Errorf("require name, flag and version counts differ")
+	}
+	if !strings.Contains(strings.Join(requires, " "), "fuse-libs") {
+		t.Errorf("requires %v do not include fuse-libs", requires)
+	}
+
+	dirnames := header.strings(rpmTagDirnames)
+	basenames := header.strings(rpmTagBasenames)
+	dirIndexes := header.int32s(rpmTagDirIndexes)
+	sizes := header.int32s(rpmTagFileSizes)
+	entries := readCPIO(t, payload)
+	if len(entries) != len(basenames) {
+		t.Fatalf("payload has %d files, header lists %d", len(entries), len(basenames))
+	}
+	for idx, base := range basenames {
+		name := "." + dirnames[dirIndexes[idx]] + base
+		size, ok := entries[name]
+		if !ok {
+			t.Errorf("%s is listed in the header but missing from the payload", name)
+			continue
+		}
+		if int64(sizes[idx]) != size {
+			t.Errorf("%s is %d bytes in the payload, header says %d", name, size, sizes[idx])
+		}
+	}
+
+	if _, err := exec.LookPath("rpm"); err != nil {
+		t.Log("rpm is not installed, skipping the rpm query check")
+		return
+	}
+	output, err := exec.Command("rpm", "-qp", "--requires", target).CombinedOutput()
+	if err != nil {
+		t.Fatalf("rpm -qp --requires failed: %v: %s", err, output)
+	}
+	if !strings.Contains(string(output), "fuse-libs") {
+		t.Errorf("rpm does not see the fuse-libs requirement: %s", output)
+	}
+	if output, err := exec.Command("rpm", "-qlp", target).CombinedOutput(); err != nil || !strings.Contains(string(output), filepath.Join(packageInstallDir, appImage)) {
+		t.Errorf("rpm -qlp did not list the AppImage: %v: %s", err, output)
+	}
+	if output, err := exec.Command("rpm", "-K", "--nosignature", target).CombinedOutput(); err != nil {
+		t.Errorf("rpm -K failed: %v: %s", err, output)
+	}
+}
+
+func sha256Sum(data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return sum[:]
+}
@@ -0,0 +1,214 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const runLogRetention = 50
+
+var logger = slog.New(outputHandler{slog.NewJSONHandler(io.Discard, nil)})
+
+type RunLog struct {
+	Path    string
+	file    *os.File
+	started time.Time
+}
+
+type RunSummary struct {
+	Path     string
+	Command  string
+	Started  time.Time
+	Duration time.Duration
+	Error    string
+	Finished bool
+}
+
+func SetLogger(l *slog.Logger) {
+	logger = slog.New(outputHandler{l.Handler()})
+}
+
+func Logger() *slog.Logger {
+	return logger
+}
+
+func RunLogDir(target TargetUser) string {
+	if stateHome := os.Getenv("XDG_STATE_HOME"); stateHome != "" && os.Geteuid() == target.UID {
+		return filepath.Join(stateHome, "cursor-installer")
+	}
+	return filepath.Join(target.HomeDir, ".local", "state", "cursor-installer")
+}
+
+func OpenRunLog(target TargetUser, command string, args []string, stream io.Writer) (*RunLog, error) {
+	dir := RunLogDir(target)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %v", err)
+	}
+	if err := os.Chmod(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to set permissions on log directory: %v", err)
+	}
+
+	started := time.Now()
+	path := filepath.Join(dir, fmt.Sprintf("run-%s-%d.jsonl", started.Format("20060102-150405"), os.Getpid()))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create run log: %v", err)
+	}
+	if os.Geteuid() == 0 && target.UID != 0 {
+		os.Chown(dir, target.UID, target.GID)
+		os.Chown(path, target.UID, target.GID)
+	}
+
+	var handler slog.Handler = slog.NewJSONHandler(file, &slog.HandlerOptions{Level: slog.LevelDebug})
+	if stream != nil {
+		handler = teeHandler{handler, slog.NewTextHandler(stream, &slog.HandlerOptions{Level: slog.LevelDebug})}
+	}
+	SetLogger(slog.New(handler))
+
+	logger.Info("run started", "command", command, "args", args, "version", InstallerVersion, "pid", os.Getpid(), "uid", os.Geteuid())
+	pruneRunLogs(dir)
+
+	return &RunLog{Path: path, file: file, started: started}, nil
+}
+
+func (l *RunLog) Close(runErr error) error {
+	if runErr != nil {
+		logger.Error("run finished", "duration", time.Since(l.started), "error", runErr.Error())
+	} else {
+		logger.Info("run finished", "duration", time.Since(l.started))
+	}
+	SetLogger(slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	return l.file.Close()
+}
+
+func RunLogs(target TargetUser) ([]RunSummary, error) {
+	matches, err := filepath.Glob(filepath.Join(RunLogDir(target), "run-*.jsonl"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list run logs: %v", err)
+	}
+
+	var runs []RunSummary
+	for _, match := range matches {
+		summary, err := summarizeRunLog(match)
+		if err != nil {
+			continue
+		}
+		runs = append(runs, summary)
+	}
+
+	sort.Slice(runs, func(a, b int) bool { return runs[a].Started.After(runs[b].Started) })
+	return runs, nil
+}
+
+func summarizeRunLog(path string) (RunSummary, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return RunSummary{}, err
+	}
+	defer file.Close()
+
+	summary := RunSummary{Path: path}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var record struct {
+			Time     time.Time     `json:"time"`
+			Msg      string        `json:"msg"`
+			Command  string        `json:"command"`
+			Duration time.Duration `json:"duration"`
+			Error    string        `json:"error"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &record) != nil {
+			continue
+		}
+		switch record.Msg {
+		case "run started":
+			summary.Started = record.Time
+			summary.Command = record.Command
+		case "run finished":
+			summary.Finished = true
+			summary.Duration = record.Duration
+			summary.Error = record.Error
+		}
+	}
+	if summary.Started.IsZero() {
+		return summary, fmt.Errorf("%s has no run start record", path)
+	}
+	return summary, scanner.Err()
+}
+
+func pruneRunLogs(dir string) {
+	matches, _ := filepath.Glob(filepath.Join(dir, "run-*.jsonl"))
+	if len(matches) <= runLogRetention {
+		return
+	}
+	sort.Strings(matches)
+	for _, match := range matches[:len(matches)-runLogRetention] {
+		os.Remove(match)
+	}
+}
+
+func (i *Installer) redactHeaders(header map[string][]string) map[string]string {
+	redacted := make(map[string]string, len(header))
+	for key, values := range header {
+		switch strings.ToLower(key) {
+		case "authorization", "proxy-authorization", "cookie", "set-cookie":
+			redacted[key] = "<redacted>"
+		default:
+			redacted[key] = strings.Join(values, ", ")
+		}
+		for custom := range i.httpOptions.Headers {
+			if strings.EqualFold(custom, key) {
+				redacted[key] = "<redacted>"
+			}
+		}
+	}
+	return redacted
+}
+
+type teeHandler []slog.Handler
+
+func (t teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range t {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t teeHandler) Handle(ctx context.Context, record slog.Record) error {
+	for _, handler := range t {
+		if handler.Enabled(ctx, record.Level) {
+			if err := handler.Handle(ctx, record.Clone()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (t teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(teeHandler, len(t))
+	for idx, handler := range t {
+		handlers[idx] = handler.WithAttrs(attrs)
+	}
+	return handlers
+}
+
+func (t teeHandler) WithGroup(name string) slog.Handler {
+	handlers := make(teeHandler, len(t))
+	for idx, handler := range t {
+		handlers[idx] = handler.WithGroup(name)
+	}
+	return handlers
+}
@@ -0,0 +1,270 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	stagedAppImage = "Cursor.AppImage.staged"
+	launcherScript = "cursor-launcher"
+)
+
+type RunningPolicy string
+
+const (
+	RunningAsk   RunningPolicy = ""
+	RunningWait  RunningPolicy = "wait"
+	RunningClose RunningPolicy = "close"
+	RunningStage RunningPolicy = "stage"
+)
+
+type RunningError struct {
+	PIDs []int
+}
+
+func (e *RunningError) Error() string {
+	return fmt.Sprintf("Cursor is currently running (pid %s)", e.PIDList())
+}
+
+func (e *RunningError) PIDList() string {
+	return joinPIDs(e.PIDs)
+}
+
+func ParseRunningPolicy(policy string) (RunningPolicy, error) {
+	switch RunningPolicy(policy) {
+	case RunningAsk, RunningWait, RunningClose, RunningStage:
+		return RunningPolicy(policy), nil
+	}
+	return "", fmt.Errorf("unknown running policy %q, expected wait, close or stage", policy)
+}
+
+func (i *Installer) SetRunningPolicy(policy RunningPolicy) {
+	i.runningPolicy = policy
+}
+
+func (i *Installer) RunningPolicy() RunningPolicy {
+	return i.runningPolicy
+}
+
+func (i *Installer) RunningInstances() ([]int, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan processes: %v", err)
+	}
+
+	targets := []string{
+		filepath.Join(installDir, appImage),
+		filepath.Join(installDir, stagedAppImage),
+	}
+	self := os.Getpid()
+
+	var pids []int
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || pid == self {
+			continue
+		}
+		if processUsesAppImage(pid, targets) {
+			pids = append(pids, pid)
+		}
+	}
+
+	return pids, nil
+}
+
+// processUsesAppImage only matches processes started from the installed
+// files: the AppImage itself, the extracted tree, or a process whose AppImage
+// runtime reports one of the installed AppImages. Other Cursor AppImages, such
+// as one run from ~/Downloads, are left alone.
+func processUsesAppImage(pid int, targets []string) bool {
+	procDir := filepath.Join("/proc", strconv.Itoa(pid))
+
+	if exe, err := os.Readlink(filepath.Join(procDir, "exe")); err == nil {
+		exe = strings.TrimSuffix(exe, " (deleted)")
+		for _, target := range targets {
+			if exe == target {
+				return true
+			}
+		}
+		if strings.HasPrefix(exe, installDir+"/") {
+			return true
+		}
+	}
+
+	environ, err := os.ReadFile(filepath.Join(procDir, "environ"))
+	if err != nil {
+		return false
+	}
+	for _, variable := range strings.Split(string(environ), "\x00") {
+		value, ok := strings.CutPrefix(variable, "APPIMAGE=")
+		if !ok {
+			continue
+		}
+		for _, target := range targets {
+			if value == target {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (i *Installer) HandleRunningInstances(ctx context.Context) error {
+	i.staging = false
+
+	pids, err := i.RunningInstances()
+	if err != nil {
+		return err
+	}
+	if len(pids) == 0 {
+		return nil
+	}
+
+	switch i.runningPolicy {
+	case RunningWait:
+		return i.waitForExit(ctx, 0)
+	case RunningClose:
+		fmt.Fprintf(stdout(ctx), "Asking Cursor to close (pid %s)\n", joinPIDs(pids))
+		logger.InfoContext(ctx, "closing cursor", "pids", pids)
+		for _, pid := range pids {
+			if err := syscall.Kill(pid, syscall.SIGTERM); err != nil && err != syscall.ESRCH {
+				return fmt.Errorf("failed to ask Cursor (pid %d) to close: %v", pid, err)
+			}
+		}
+		return i.waitForExit(ctx, 30*time.Second)
+	case RunningStage:
+		if i.extracted() {
+			i.warn(ctx, "Cursor is running, the new version was installed next to it and is used on the next launch")
+			return nil
+		}
+		i.staging = true
+		i.warn(ctx, "Cursor is running, the update was staged and applies on the next launch")
+		return nil
+	}
+
+	return &RunningError{PIDs: pids}
+}
+
+func (i *Installer) waitForExit(ctx context.Context, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		pids, err := i.RunningInstances()
+		if err != nil {
+			return err
+		}
+		if len(pids) == 0 {
+			return nil
+		}
+		if timeout > 0 && time.Now().After(deadline) {
+			return fmt.Errorf("Cursor is still running after %s (pid %s)", timeout, joinPIDs(pids))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func (i *Installer) appImagePath() string {
+	if i.staging {
+		return filepath.Join(installDir, stagedAppImage)
+	}
+	return filepath.Join(installDir, appImage)
+}
+
+func (i *Installer) launchPath() string {
+	if i.extracted() {
+		return filepath.Join(i.extractedDir(), "AppRun")
+	}
+
+	launcher := filepath.Join(installDir, launcherScript)
+	if i.staging {
+		return launcher
+	}
+	if _, err := os.Stat(launcher); err == nil {
+		return launcher
+	}
+	return filepath.Join(installDir, appImage)
+}
+
+func (i *Installer) installLauncher(ctx context.Context) error {
+	script := fmt.Sprintf(`#!/bin/sh
+# Installed by cursor-installer: swaps in a staged update before launching Cursor.
+APPIMAGE="%s"
+STAGED="%s"
+if [ -f "$STAGED" ] && ! pgrep -f "^$APPIMAGE( |\$)" >/dev/null 2>&1 && ! grep -qsxzF "APPIMAGE=$APPIMAGE" /proc/[0-9]*/environ; then
+	if [ -w "$(dirname "$APPIMAGE")" ]; then
+		mv -f "$STAGED" "$APPIMAGE"
+	elif command -v pkexec >/dev/null 2>&1; then
+		pkexec mv -f "$STAGED" "$APPIMAGE"
+	fi
+fi
+exec "$APPIMAGE" "$@"
+`, filepath.Join(installDir, appImage), filepath.Join(installDir, stagedAppImage))
+
+	tmpFile, err := os.CreateTemp("", "cursor-launcher-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.WriteString(script); err != nil {
+		return fmt.Errorf("failed to write launcher: %v", err)
+	}
+	tmpFile.Close()
+
+	targetPath := filepath.Join(installDir, launcherScript)
+	existed := pathExists(targetPath)
+	if err := runSudo(ctx, "install", "-m", "755", tmpFile.Name(), targetPath); err != nil {
+		return fmt.Errorf("failed to install launcher (sudo error): %v", err)
+	}
+	i.recordFile(targetPath, existed)
+
+	return nil
+}
+
+func (i *Installer) PromoteStagedUpdate(ctx context.Context) error {
+	stagedPath := filepath.Join(installDir, stagedAppImage)
+	if _, err := os.Stat(stagedPath); os.IsNotExist(err) {
+		return nil
+	}
+
+	pids, err := i.RunningInstances()
+	if err != nil {
+		return err
+	}
+	if len(pids) > 0 {
+		return nil
+	}
+
+	if err := runSudo(ctx, "mv", "-f", stagedPath, filepath.Join(installDir, appImage)); err != nil {
+		return fmt.Errorf("failed to apply staged update (sudo error): %v", err)
+	}
+
+	metadata, err := i.readMetadata(ctx)
+	if err != nil || metadata == nil || metadata.StagedVersion == "" {
+		return err
+	}
+	metadata.Version = metadata.StagedVersion
+	metadata.Checksum = metadata.StagedChecksum
+	metadata.StagedVersion = ""
+	metadata.StagedChecksum = ""
+	return i.writeMetadata(ctx, metadata)
+}
+
+func joinPIDs(pids []int) string {
+	parts := make([]string, len(pids))
+	for idx, pid := range pids {
+		parts[idx] = strconv.Itoa(pid)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,247 @@
+package app
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	installerReleaseURL = "https://api.github.com/repos/lutefd/cursor-installer/releases/latest"
+	installerBinary     = "cursor-installer"
+	installerChecksums  = "checksums.txt"
+)
+
+type InstallerRelease struct {
+	Version      string
+	Archive      string
+	ArchiveURL   string
+	ChecksumsURL string
+}
+
+type InstallerDownload struct {
+	Path     string
+	Checksum string
+}
+
+type githubRelease struct {
+	TagName string `json:"tag_name"`
+	Assets  []struct {
+		Name               string `json:"name"`
+		BrowserDownloadURL string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+func (r *InstallerRelease) Newer() bool {
+	if InstallerVersion == devVersion {
+		return false
+	}
+	return compareVersions(r.Version, InstallerVersion) > 0
+}
+
+func (i *Installer) LatestInstallerRelease(ctx context.Context, source string) (*InstallerRelease, error) {
+	if source == "" {
+		source = installerReleaseURL
+	}
+
+	resp, err := i.httpGet(ctx, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch installer release: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch installer release: %s returned %s", source, resp.Status)
+	}
+
+	var latest githubRelease
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 4<<20)).Decode(&latest); err != nil {
+		return nil, fmt.Errorf("failed to parse installer release: %v", err)
+	}
+	if latest.TagName == "" {
+		return nil, fmt.Errorf("installer release at %s has no tag", source)
+	}
+
+	release := &InstallerRelease{
+		Version: strings.TrimPrefix(latest.TagName, "v"),
+	}
+	release.Archive = fmt.Sprintf("%s_%s_%s_%s.tar.gz", installerBinary, release.Version, runtime.GOOS, runtime.GOARCH)
+	for _, asset := range latest.Assets {
+		switch asset.Name {
+		case release.Archive:
+			release.ArchiveURL = asset.BrowserDownloadURL
+		case installerChecksums:
+			release.ChecksumsURL = asset.BrowserDownloadURL
+		}
+	}
+
+	if release.ArchiveURL == "" {
+		return nil, fmt.Errorf("installer release %s has no build for %s/%s", release.Version, runtime.GOOS, runtime.GOARCH)
+	}
+	if release.ChecksumsURL == "" {
+		return nil, fmt.Errorf("installer release %s does not publish %s", release.Version, installerChecksums)
+	}
+
+	logger.InfoContext(ctx, "installer release", "version", release.Version, "archive", release.Archive)
+	return release, nil
+}
+
+func (i *Installer) DownloadInstallerRelease(ctx context.Context, release *InstallerRelease) (*InstallerDownload, error) {
+	resp, err := i.httpGet(ctx, release.ArchiveURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %v", release.Archive, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: server returned %s", release.Archive, resp.Status)
+	}
+
+	out, err := os.CreateTemp("", "cursor-installer-*.tar.gz")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file: %v", err)
+	}
+	defer out.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(out, hash), resp.Body); err != nil {
+		os.Remove(out.Name())
+		return nil, fmt.Errorf("failed to save download: %v", err)
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(out.Name())
+		return nil, fmt.Errorf("failed to save download: %v", err)
+	}
+
+	return &InstallerDownload{
+		Path:     out.Name(),
+		Checksum: hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+func (i *Installer) VerifyInstallerRelease(ctx context.Context, release *InstallerRelease, download *InstallerDownload) error {
+	resp, err := i.httpGet(ctx, release.ChecksumsURL)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %v", installerChecksums, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download %s: server returned %s", installerChecksums, resp.Status)
+	}
+
+	scanner := bufio.NewScanner(io.LimitReader(resp.Body, 1<<20))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != release.Archive {
+			continue
+		}
+		if !strings.EqualFold(fields[0], download.Checksum) {
+			return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", release.Archive, fields[0], download.Checksum)
+		}
+		logger.InfoContext(ctx, "installer checksum verified", "archive", release.Archive, "sha256", download.Checksum)
+		return nil
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %v", installerChecksums, err)
+	}
+	return fmt.Errorf("%s has no entry for %s", installerChecksums, release.Archive)
+}
+
+func (i *Installer) DiscardInstallerDownload(download *InstallerDownload) {
+	if download != nil {
+		os.Remove(download.Path)
+	}
+}
+
+func (i *Installer) ReplaceExecutable(ctx context.Context, download *InstallerDownload) (string, error) {
+	defer i.DiscardInstallerDownload(download)
+
+	executable, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate the running executable: %v", err)
+	}
+	if executable, err = filepath.EvalSymlinks(executable); err != nil {
+		return "", fmt.Errorf("failed to resolve the running executable: %v", err)
+	}
+
+	staged, err := os.CreateTemp(filepath.Dir(executable), "."+installerBinary+"-*")
+	if errors.Is(err, os.ErrPermission) {
+		staged, err = os.CreateTemp("", installerBinary+"-*")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to create file: %v", err)
+	}
+	defer os.Remove(staged.Name())
+
+	if err := extractInstallerBinary(download.Path, staged); err != nil {
+		staged.Close()
+		return "", err
+	}
+	if err := staged.Close(); err != nil {
+		return "", fmt.Errorf("failed to write %s: %v", staged.Name(), err)
+	}
+	if err := os.Chmod(staged.Name(), 0755); err != nil {
+		return "", fmt.Errorf("failed to set permissions: %v", err)
+	}
+
+	if filepath.Dir(staged.Name()) == filepath.Dir(executable) {
+		if err := os.Rename(staged.Name(), executable); err != nil {
+			return "", fmt.Errorf("failed to replace %s: %v", executable, err)
+		}
+		return executable, nil
+	}
+
+	next := executable + ".new"
+	if err := runSudo(ctx, "install", "-m", "755", staged.Name(), next); err != nil {
+		return "", fmt.Errorf("failed to stage %s (sudo error): %v", next, err)
+	}
+	if err := runSudo(ctx, "mv", "-f", next, executable); err != nil {
+		return "", fmt.Errorf("failed to replace %s (sudo error): %v", executable, err)
+	}
+	return executable, nil
+}
+
+func extractInstallerBinary(archive string, out io.Writer) error {
+	file, err := os.Open(archive)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %v", archive, err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", archive, err)
+	}
+	defer gz.Close()
+
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return fmt.Errorf("%s does not contain %s", filepath.Base(archive), installerBinary)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %v", archive, err)
+		}
+		if header.Typeflag != tar.TypeReg || filepath.Base(header.Name) != installerBinary {
+			continue
+		}
+		if _, err := io.Copy(out, reader); err != nil {
+			return fmt.Errorf("failed to extract %s: %v", installerBinary, err)
+		}
+		return nil
+	}
+}
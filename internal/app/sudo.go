@@ -0,0 +1,38 @@
+package app
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+const cancelGracePeriod = 5 * time.Second
+
+func commandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	logger.DebugContext(ctx, "exec", "command", name, "args", args)
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(syscall.SIGTERM)
+	}
+	cmd.WaitDelay = cancelGracePeriod
+	return cmd
+}
+
+func sudoCommand(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := commandContext(ctx, "sudo", append([]string{"-S"}, args...)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = stdout(ctx)
+	cmd.Stderr = stderr(ctx)
+	return cmd
+}
+
+func runSudo(ctx context.Context, args ...string) error {
+	started := time.Now()
+	err := sudoCommand(ctx, args...).Run()
+	if err != nil {
+		logger.WarnContext(ctx, "exec failed", "command", "sudo", "args", args, "duration", time.Since(started), "error", err.Error())
+	}
+	return err
+}
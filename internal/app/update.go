@@ -1,17 +1,22 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"os"
 )
 
-func (i *Installer) CheckForUpdates() (bool, error) {
-	metadata, err := i.readMetadata()
+func (i *Installer) CheckForUpdates(ctx context.Context) (bool, error) {
+	if err := i.PromoteStagedUpdate(ctx); err != nil {
+		return false, err
+	}
+
+	metadata, err := i.readMetadata(ctx)
 	if err != nil {
 		return false, fmt.Errorf("failed to read metadata: %v", err)
 	}
 
-	if err := i.DownloadCursor(); err != nil {
+	if err := i.DownloadCursor(ctx); err != nil {
 		return false, fmt.Errorf("failed to check for updates: %v", err)
 	}
 
@@ -26,11 +31,11 @@ func (i *Installer) CheckForUpdates() (bool, error) {
 		return false, fmt.Errorf("no metadata found, cannot compare versions")
 	}
 
-	needsUpdate := latestVersion != metadata.Version
+	needsUpdate := latestVersion != metadata.CurrentVersion() && latestVersion != metadata.StagedVersion
 	if !needsUpdate {
 		os.Remove(appImage)
 	} else {
-		if err := i.MakeExecutable(); err != nil {
+		if err := i.MakeExecutable(ctx); err != nil {
 			os.Remove(appImage)
 			return false, fmt.Errorf("failed to make file executable: %v", err)
 		}
@@ -0,0 +1,225 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+type TargetUser struct {
+	Name    string
+	HomeDir string
+	UID     int
+	GID     int
+}
+
+func ResolveTargetUsers(forUser string, allUsers bool) ([]TargetUser, error) {
+	if forUser != "" && allUsers {
+		return nil, fmt.Errorf("--for-user and --all-users cannot be used together")
+	}
+
+	if allUsers {
+		return localUsers()
+	}
+
+	if forUser != "" {
+		u, err := user.Lookup(forUser)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find user %s: %v", forUser, err)
+		}
+		target, err := newTargetUser(u)
+		if err != nil {
+			return nil, err
+		}
+		return []TargetUser{target}, nil
+	}
+
+	target, err := InvokingUser()
+	if err != nil {
+		return nil, err
+	}
+	return []TargetUser{target}, nil
+}
+
+func InvokingUser() (TargetUser, error) {
+	var u *user.User
+	var err error
+
+	if os.Geteuid() == 0 {
+		if name := os.Getenv("SUDO_USER"); name != "" && name != "root" {
+			u, err = user.Lookup(name)
+		} else if uid := os.Getenv("PKEXEC_UID"); uid != "" {
+			u, err = user.LookupId(uid)
+		}
+		if err != nil {
+			return TargetUser{}, fmt.Errorf("failed to find invoking user: %v", err)
+		}
+	}
+
+	if u == nil {
+		u, err = user.Current()
+		if err != nil {
+			return TargetUser{}, fmt.Errorf("failed to get current user: %v", err)
+		}
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			u.HomeDir = homeDir
+		}
+	}
+
+	return newTargetUser(u)
+}
+
+func localUsers() ([]TargetUser, error) {
+	file, err := os.Open("/etc/passwd")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read user database: %v", err)
+	}
+	defer file.Close()
+
+	var users []TargetUser
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ":")
+		if len(fields) < 7 {
+			continue
+		}
+
+		uid, err := strconv.Atoi(fields[2])
+		if err != nil || uid < 1000 || uid >= 65534 {
+			continue
+		}
+		shell := filepath.Base(fields[6])
+		if shell == "nologin" || shell == "false" {
+			continue
+		}
+		if info, err := os.Stat(fields[5]); err != nil || !info.IsDir() {
+			continue
+		}
+
+		gid, err := strconv.Atoi(fields[3])
+		if err != nil {
+			continue
+		}
+
+		users = append(users, TargetUser{
+			Name:    fields[0],
+			HomeDir: fields[5],
+			UID:     uid,
+			GID:     gid,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read user database: %v", err)
+	}
+
+	if len(users) == 0 {
+		return nil, fmt.Errorf("no local users with a home directory found")
+	}
+
+	return users, nil
+}
+
+func newTargetUser(u *user.User) (TargetUser, error) {
+	uid, err := strconv.Atoi(u.Uid)
+	if err != nil {
+		return TargetUser{}, fmt.Errorf("invalid uid for user %s: %v", u.Username, err)
+	}
+	gid, err := strconv.Atoi(u.Gid)
+	if err != nil {
+		return TargetUser{}, fmt.Errorf("invalid gid for user %s: %v", u.Username, err)
+	}
+
+	return TargetUser{
+		Name:    u.Username,
+		HomeDir: u.HomeDir,
+		UID:     uid,
+		GID:     gid,
+	}, nil
+}
+
+func (i *Installer) ForUser(target TargetUser) *Installer {
+	scoped := *i
+	scoped.user = &target
+	return &scoped
+}
+
+func (i *Installer) targetUser() (TargetUser, error) {
+	if i.user != nil {
+		return *i.user, nil
+	}
+	return InvokingUser()
+}
+
+func (i *Installer) homeDir() (string, error) {
+	target, err := i.targetUser()
+	if err != nil {
+		return "", err
+	}
+	if target.HomeDir == "" {
+		return "", fmt.Errorf("failed to get home directory for user %s", target.Name)
+	}
+	return target.HomeDir, nil
+}
+
+// mkdirAllOwned and writeUserFile run as the target user when the installer
+// runs as root, so a symlink planted in the user's home cannot redirect the
+// write to a file only root may touch.
+func (i *Installer) mkdirAllOwned(dir string) error {
+	return i.runUserCommand(nil, "mkdir", "-p", dir)
+}
+
+func (i *Installer) writeUserFile(path string, data []byte, perm os.FileMode) error {
+	existed := pathExists(path)
+	mode := strconv.FormatUint(uint64(perm.Perm()), 8)
+	if err := i.runUserCommand(data, "tee", path); err != nil {
+		return err
+	}
+	if err := i.runUserCommand(nil, "chmod", mode, path); err != nil {
+		return err
+	}
+	i.recordFile(path, existed)
+	return nil
+}
+
+func (i *Installer) runUserCommand(input []byte, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	if input != nil {
+		cmd.Stdin = bytes.NewReader(input)
+	}
+	if err := i.runAsTargetUser(cmd); err != nil {
+		return err
+	}
+	var output bytes.Buffer
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(output.String()))
+	}
+	return nil
+}
+
+func (i *Installer) runAsTargetUser(cmd *exec.Cmd) error {
+	if os.Geteuid() != 0 {
+		return nil
+	}
+
+	target, err := i.targetUser()
+	if err != nil {
+		return err
+	}
+	if target.UID == 0 {
+		return nil
+	}
+
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Credential: &syscall.Credential{Uid: uint32(target.UID), Gid: uint32(target.GID)},
+	}
+	cmd.Env = append(os.Environ(), "HOME="+target.HomeDir, "USER="+target.Name, "LOGNAME="+target.Name)
+	return nil
+}
@@ -1,45 +1,179 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
 )
 
-const InstallerVersion = "0.4.0"
+const devVersion = "dev"
+
+// cursorVersion accepts anything that is safe as a single path component, so
+// versions become cache file and install directory names without escaping.
+var cursorVersion = regexp.MustCompile(`^\d[0-9A-Za-z._+-]*$`)
+
+var (
+	InstallerVersion   = devVersion
+	InstallerCommit    string
+	InstallerBuildDate string
+)
 
 type VersionInfo struct {
-	CursorVersion    string
-	InstallerVersion string
-	IsInstalled      bool
+	CursorVersion      string
+	InstallerVersion   string
+	InstallerCommit    string
+	InstallerBuildDate string
+	InstallerUpdate    string
+	IsInstalled        bool
+	Scope              string
+	InstallPath        string
+	InstallDate        time.Time
+	LastUpdate         time.Time
+	Channel            string
+	Checksum           string
+	LatestVersion      string
+	LatestChecked      bool
+	Integration        []DiagnosticResult
 }
 
-func (i *Installer) GetVersionInfo() (*VersionInfo, error) {
-	info := &VersionInfo{
-		InstallerVersion: InstallerVersion,
+// SetBuildInfo prefers the values goreleaser injects with -ldflags and falls
+// back to the module version and VCS stamp that go install records.
+func SetBuildInfo(version, commit, date string) {
+	InstallerVersion = strings.TrimPrefix(version, "v")
+	InstallerCommit = commit
+	InstallerBuildDate = date
+
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		if InstallerVersion == "" {
+			InstallerVersion = devVersion
+		}
+		return
+	}
+	if InstallerVersion == "" && releasedModule(build.Main.Version) {
+		InstallerVersion = strings.TrimPrefix(build.Main.Version, "v")
+	}
+	if InstallerVersion == "" {
+		InstallerVersion = devVersion
 	}
 
-	cursorPath := filepath.Join(installDir, appImage)
-	if _, err := os.Stat(cursorPath); err != nil {
-		if os.IsNotExist(err) {
-			info.IsInstalled = false
-			return info, nil
+	var modified bool
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if InstallerCommit == "" {
+				InstallerCommit = setting.Value
+			}
+		case "vcs.time":
+			if InstallerBuildDate == "" {
+				InstallerBuildDate = setting.Value
+			}
+		case "vcs.modified":
+			modified = setting.Value == "true"
 		}
+	}
+	if modified && InstallerCommit != "" && commit == "" {
+		InstallerCommit += "-dirty"
+	}
+}
+
+// Local builds get a v0.0.0 pseudo-version (with +dirty for uncommitted
+// changes) rather than a release, so those are reported as dev builds.
+func releasedModule(version string) bool {
+	return version != "" && version != "(devel)" && !strings.HasPrefix(version, "v0.0.0-") && !strings.HasSuffix(version, "+dirty")
+}
+
+func installScope() string {
+	home, err := os.UserHomeDir()
+	if err == nil && home != "" && strings.HasPrefix(installDir, filepath.Clean(home)+string(filepath.Separator)) {
+		return "user"
+	}
+	return "system"
+}
+
+func (i *Installer) GetVersionInfo(ctx context.Context) (*VersionInfo, error) {
+	info := &VersionInfo{
+		InstallerVersion:   InstallerVersion,
+		InstallerCommit:    InstallerCommit,
+		InstallerBuildDate: InstallerBuildDate,
+		Scope:              installScope(),
+		InstallPath:        installDir,
+	}
+
+	installed, err := isInstalled()
+	if err != nil {
 		return nil, fmt.Errorf("failed to check cursor installation: %v", err)
 	}
+	if !installed {
+		info.IsInstalled = false
+		return info, nil
+	}
 
-	metadata, err := i.readMetadata()
+	metadata, err := i.readMetadata(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read metadata: %v", err)
 	}
 
+	i.applyMetadataLayout(metadata)
+
 	if metadata != nil {
-		info.CursorVersion = metadata.Version
+		info.CursorVersion = metadata.CurrentVersion()
 		info.IsInstalled = true
+		info.InstallPath = metadata.InstallPath
+		info.InstallDate = metadata.InstallDate
+		info.LastUpdate = metadata.LastUpdateDate
+		info.Checksum = metadata.CurrentChecksum()
 	} else {
 		info.CursorVersion = "unknown"
 		info.IsInstalled = true
 	}
 
+	fuse := checkFuse()
+	if i.extracted() {
+		fuse = DiagnosticResult{Name: "FUSE", Status: CheckPass, Detail: "not required for extracted installs"}
+	}
+	info.Integration = []DiagnosticResult{fuse, checkDesktopEntry()}
+
 	return info, nil
 }
+
+func validateVersion(version string) error {
+	if !cursorVersion.MatchString(version) || strings.Contains(version, "..") {
+		return fmt.Errorf("invalid Cursor version %q", version)
+	}
+	return nil
+}
+
+func compareVersions(a, b string) int {
+	partsA := strings.FieldsFunc(a, func(r rune) bool { return r == '.' || r == '-' })
+	partsB := strings.FieldsFunc(b, func(r rune) bool { return r == '.' || r == '-' })
+
+	for idx := 0; idx < len(partsA) || idx < len(partsB); idx++ {
+		var partA, partB string
+		if idx < len(partsA) {
+			partA = partsA[idx]
+		}
+		if idx < len(partsB) {
+			partB = partsB[idx]
+		}
+
+		numA, errA := strconv.Atoi(partA)
+		numB, errB := strconv.Atoi(partB)
+		switch {
+		case errA == nil && errB == nil && numA != numB:
+			if numA < numB {
+				return -1
+			}
+			return 1
+		case (errA != nil || errB != nil) && partA != partB:
+			return strings.Compare(partA, partB)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,186 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const versionsPath = "/versions"
+
+type AvailableVersion struct {
+	Version     string    `json:"version"`
+	Current     bool      `json:"current,omitempty"`
+	Latest      bool      `json:"latest,omitempty"`
+	Cached      bool      `json:"cached,omitempty"`
+	Mirror      bool      `json:"mirror,omitempty"`
+	Size        int64     `json:"size,omitempty"`
+	InstallDate time.Time `json:"install_date"`
+	LastUpdate  time.Time `json:"last_update"`
+	Downloaded  time.Time `json:"downloaded"`
+}
+
+func (i *Installer) SetTargetVersion(version string) {
+	i.targetVersion = version
+}
+
+func (i *Installer) versionURL() string {
+	if i.targetVersion == "" {
+		return i.downloadURL()
+	}
+
+	parsed, err := url.Parse(i.downloadURL())
+	if err != nil {
+		return i.downloadURL()
+	}
+	query := parsed.Query()
+	query.Set("version", i.targetVersion)
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
+
+func (i *Installer) mirrorURL(path string) string {
+	parsed, err := url.Parse(strings.TrimSuffix(i.mirror, "/"))
+	if err != nil {
+		return ""
+	}
+	parsed.Path = path
+	parsed.RawQuery = ""
+	return parsed.String()
+}
+
+// LatestVersion only needs the Content-Disposition header, so it asks with
+// HEAD and falls back to a GET that is closed before the body is read.
+func (i *Installer) LatestVersion(ctx context.Context) (string, error) {
+	resp, err := i.httpRequest(ctx, http.MethodHead, i.downloadURL())
+	if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented) {
+		resp.Body.Close()
+		resp, err = i.httpGet(ctx, i.downloadURL())
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to check the latest version: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to check the latest version: %s returned %s", i.downloadURL(), resp.Status)
+	}
+
+	version, err := versionFromResponse(resp)
+	if err != nil {
+		return "", fmt.Errorf("failed to check the latest version: %v", err)
+	}
+	if version == "" {
+		return "", fmt.Errorf("failed to check the latest version: %s did not report one", i.downloadURL())
+	}
+	return version, nil
+}
+
+func (i *Installer) mirrorVersions(ctx context.Context) ([]AvailableVersion, error) {
+	location := i.mirrorURL(versionsPath)
+	resp, err := i.httpGet(ctx, location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mirror versions: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list mirror versions: %s returned %s", location, resp.Status)
+	}
+
+	var versions []AvailableVersion
+	if err := json.NewDecoder(resp.Body).Decode(&versions); err != nil {
+		return nil, fmt.Errorf("failed to parse mirror versions: %v", err)
+	}
+	for _, version := range versions {
+		if err := validateVersion(version.Version); err != nil {
+			return nil, fmt.Errorf("failed to parse mirror versions: %v", err)
+		}
+	}
+	return versions, nil
+}
+
+func (i *Installer) AvailableVersions(ctx context.Context) ([]AvailableVersion, error) {
+	versions := make(map[string]*AvailableVersion)
+	entry := func(version string) *AvailableVersion {
+		if versions[version] == nil {
+			versions[version] = &AvailableVersion{Version: version}
+		}
+		return versions[version]
+	}
+
+	metadata, err := i.readMetadata(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata: %v", err)
+	}
+	if metadata != nil {
+		current := entry(metadata.CurrentVersion())
+		current.Current = true
+		current.InstallDate = metadata.InstallDate
+		current.LastUpdate = metadata.LastUpdateDate
+	}
+
+	cached, err := i.CacheEntries()
+	if err != nil {
+		return nil, err
+	}
+	for _, cache := range cached {
+		version := entry(cache.Version)
+		version.Cached = true
+		version.Size = cache.Size
+		if cache.Modified.After(version.Downloaded) {
+			version.Downloaded = cache.Modified
+		}
+	}
+
+	if i.mirror != "" {
+		mirrored, err := i.mirrorVersions(ctx)
+		if err != nil {
+			i.warn(ctx, err.Error())
+		}
+		for _, mirror := range mirrored {
+			version := entry(mirror.Version)
+			version.Mirror = true
+			if version.Size == 0 {
+				version.Size = mirror.Size
+			}
+		}
+	}
+
+	if latest, err := i.LatestVersion(ctx); err != nil {
+		i.warn(ctx, err.Error())
+	} else {
+		entry(latest).Latest = true
+	}
+
+	list := make([]AvailableVersion, 0, len(versions))
+	for _, version := range versions {
+		list = append(list, *version)
+	}
+	sort.Slice(list, func(a, b int) bool { return compareVersions(list[a].Version, list[b].Version) > 0 })
+	return list, nil
+}
+
+func (i *Installer) cachedVersions() ([]AvailableVersion, error) {
+	entries, err := i.CacheEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var versions []AvailableVersion
+	for _, entry := range entries {
+		if seen[entry.Version] {
+			continue
+		}
+		seen[entry.Version] = true
+		versions = append(versions, AvailableVersion{Version: entry.Version, Size: entry.Size, Downloaded: entry.Modified})
+	}
+	sort.Slice(versions, func(a, b int) bool { return compareVersions(versions[a].Version, versions[b].Version) > 0 })
+	return versions, nil
+}
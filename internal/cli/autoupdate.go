@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/lutefd/cursor-installer/internal/app"
+	"github.com/lutefd/cursor-installer/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func newAutoUpdateCmd() *cobra.Command {
+	var scope string
+
+	cmd := &cobra.Command{
+		Use:   "auto-update",
+		Short: "Manage automatic background updates through a systemd timer",
+	}
+	cmd.PersistentFlags().StringVar(&scope, "scope", "user", "Timer scope (user|system)")
+
+	var schedule string
+	enableCmd := &cobra.Command{
+		Use:   "enable",
+		Short: "Install and start the update timer",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			autoUpdateScope, err := app.ParseAutoUpdateScope(scope)
+			if err != nil {
+				return err
+			}
+			lock, err := acquireLock(cmd)
+			if err != nil {
+				return err
+			}
+			defer lock.Release()
+
+			if err := app.NewInstaller(false, false, false).EnableAutoUpdate(cmd.Context(), autoUpdateScope, schedule); err != nil {
+				return err
+			}
+			fmt.Printf("Automatic updates enabled (%s scope, schedule %q)\n", autoUpdateScope, schedule)
+			return nil
+		},
+	}
+	enableCmd.Flags().StringVar(&schedule, "schedule", "daily", "systemd OnCalendar expression for update runs")
+
+	disableCmd := &cobra.Command{
+		Use:   "disable",
+		Short: "Stop and remove the update timer",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			autoUpdateScope, err := app.ParseAutoUpdateScope(scope)
+			if err != nil {
+				return err
+			}
+			lock, err := acquireLock(cmd)
+			if err != nil {
+				return err
+			}
+			defer lock.Release()
+
+			if err := app.NewInstaller(false, false, false).DisableAutoUpdate(cmd.Context(), autoUpdateScope); err != nil {
+				return err
+			}
+			fmt.Printf("Automatic updates disabled (%s scope)\n", autoUpdateScope)
+			return nil
+		},
+	}
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the update timer state and recent runs",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			autoUpdateScope, err := app.ParseAutoUpdateScope(scope)
+			if err != nil {
+				return err
+			}
+			status, err := app.NewInstaller(false, false, false).AutoUpdateStatus(autoUpdateScope)
+			if err != nil {
+				return err
+			}
+			fmt.Println(ui.NewAutoUpdateStatusDisplay(status).View())
+			return nil
+		},
+	}
+
+	cmd.AddCommand(enableCmd, disableCmd, statusCmd)
+
+	return cmd
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/lutefd/cursor-installer/internal/app"
+	"github.com/lutefd/cursor-installer/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func newCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the local cache of downloaded AppImages",
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List cached downloads",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			installer := app.NewInstaller(false, false, false)
+			dir, err := installer.CacheDir()
+			if err != nil {
+				return err
+			}
+			entries, err := installer.CacheEntries()
+			if err != nil {
+				return err
+			}
+			fmt.Println(ui.CacheListView(dir, entries))
+			return nil
+		},
+	}
+
+	var keep int
+	pruneCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove all but the most recently used versions",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if keep < 0 {
+				return fmt.Errorf("--keep must not be negative")
+			}
+			lock, err := acquireLock(cmd)
+			if err != nil {
+				return err
+			}
+			defer lock.Release()
+
+			if !cmd.Flags().Changed("keep") && cfg.Cache.Retention > 0 {
+				keep = cfg.Cache.Retention
+			}
+			removed, err := app.NewInstaller(false, false, false).PruneCache(keep)
+			fmt.Println(ui.CacheRemovedView(removed))
+			return err
+		},
+	}
+	pruneCmd.Flags().IntVar(&keep, "keep", 2, "Number of versions to keep (defaults to cache.retention when set)")
+
+	clearCmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Remove every cached download",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			lock, err := acquireLock(cmd)
+			if err != nil {
+				return err
+			}
+			defer lock.Release()
+
+			removed, err := app.NewInstaller(false, false, false).ClearCache()
+			fmt.Println(ui.CacheRemovedView(removed))
+			return err
+		},
+	}
+
+	cmd.AddCommand(listCmd, pruneCmd, clearCmd)
+
+	return cmd
+}
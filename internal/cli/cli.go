@@ -1,7 +1,13 @@
 package cli
 
 import (
+	"bufio"
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/lutefd/cursor-installer/internal/app"
@@ -14,6 +20,16 @@ var (
 	forceInstall      bool
 	showVersion       bool
 	configureSettings bool
+	extensionsFile    string
+	profileFile       string
+	forUser           string
+	allUsers          bool
+	nonInteractive    bool
+	whenRunning       string
+	extract           bool
+	reportFormat      string
+	showChangelog     bool
+	checkLatest       bool
 )
 
 func Execute() error {
@@ -21,17 +37,48 @@ func Execute() error {
 		Use:   "cursor-installer",
 		Short: "Install Cursor Editor",
 		Long:  ui.GetLongDescription(),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if !showVersion {
+				openRunLog(cmd)
+			}
+			return loadConfig(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if showVersion {
-				installer := app.NewInstaller(false, false, false)
-				info, err := installer.GetVersionInfo()
+				info, err := versionInfo(cmd.Context(), checkLatest)
 				display := ui.NewVersionDisplay(info, err)
 				fmt.Println(display.View())
 				return nil
 			}
 
-			model := ui.NewModel(downloadOnly, forceInstall, configureSettings)
-			program := tea.NewProgram(model)
+			if reportFormat != ui.ReportText && reportFormat != ui.ReportJSON {
+				return fmt.Errorf("--report must be text or json, got %q", reportFormat)
+			}
+
+			lock, err := acquireLock(cmd)
+			if err != nil {
+				return err
+			}
+			defer lock.Release()
+
+			opts, err := installOptions()
+			if err != nil {
+				return err
+			}
+			opts.DownloadOnly = downloadOnly
+			opts.ForceInstall = forceInstall
+			opts.Changelog = showChangelog || !(nonInteractive || reportFormat == ui.ReportJSON)
+
+			ctx, stop := signalContext(cmd)
+			defer stop()
+
+			model := ui.NewModel(ctx, opts)
+			if nonInteractive || reportFormat == ui.ReportJSON {
+				cmd.SilenceUsage = true
+				return model.RunPlain(os.Stdout, reportFormat)
+			}
+
+			program := tea.NewProgram(model, tea.WithoutSignalHandler())
 
 			if _, err := program.Run(); err != nil {
 				return fmt.Errorf("installation failed: %v", err)
@@ -43,7 +90,182 @@ func Execute() error {
 	rootCmd.Flags().BoolVarP(&downloadOnly, "download-only", "d", false, "Only download Cursor without installing")
 	rootCmd.Flags().BoolVarP(&forceInstall, "force", "f", false, "Force installation even if Cursor is already installed")
 	rootCmd.Flags().BoolVarP(&showVersion, "version", "v", false, "Display version information")
+	rootCmd.Flags().BoolVar(&checkLatest, "check", false, "With --version, also look up the latest Cursor and installer releases")
 	rootCmd.Flags().BoolVarP(&configureSettings, "config", "c", false, "Configure Cursor settings after installation")
+	rootCmd.Flags().StringVarP(&extensionsFile, "extensions", "e", "", "Provision extensions from a list file after installation")
+	rootCmd.Flags().StringVarP(&profileFile, "profile", "p", "", "Apply an exported settings profile after installation")
+	rootCmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "Run without the interactive UI, printing plain progress lines")
+	rootCmd.Flags().StringVar(&whenRunning, "when-running", "", "What to do when Cursor is running during an update (wait|close|stage)")
+	rootCmd.Flags().BoolVar(&extract, "extract", false, "Install the extracted AppImage contents so FUSE is not required")
+	rootCmd.Flags().BoolVar(&showChangelog, "changelog", false, "Print the release notes of an available update in non-interactive mode")
+	rootCmd.Flags().StringVar(&reportFormat, "report", ui.ReportText, "Format of the non-interactive run summary (text|json), json implies --non-interactive")
+	rootCmd.PersistentFlags().StringVar(&forUser, "for-user", "", "Configure Cursor for the given user instead of the invoking one")
+	rootCmd.PersistentFlags().BoolVar(&allUsers, "all-users", false, "Configure Cursor for every local user with a home directory")
+	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Stream the run log to the terminal")
+	rootCmd.PersistentFlags().BoolVar(&waitForLock, "wait", false, "Wait for another running cursor-installer to finish instead of failing")
+	addDownloadFlags(rootCmd.PersistentFlags())
+
+	rootCmd.AddCommand(newExtensionsCmd())
+	rootCmd.AddCommand(newMigrateCmd())
+	rootCmd.AddCommand(newConfigureCmd())
+	rootCmd.AddCommand(newAutoUpdateCmd())
+	rootCmd.AddCommand(newDoctorCmd())
+	rootCmd.AddCommand(newRepairCmd())
+	rootCmd.AddCommand(newPackageCmd())
+	rootCmd.AddCommand(newCacheCmd())
+	rootCmd.AddCommand(newMirrorCmd())
+	rootCmd.AddCommand(newConfigCmd())
+	rootCmd.AddCommand(newLogsCmd())
+	rootCmd.AddCommand(newVersionsCmd())
+	rootCmd.AddCommand(newSelfUpdateCmd())
+
+	err := rootCmd.Execute()
+	closeRunLog(err)
+	return err
+}
+
+func installOptions() (ui.Options, error) {
+	users, err := targetUsers()
+	if err != nil {
+		return ui.Options{}, err
+	}
+
+	runningPolicy, err := app.ParseRunningPolicy(cfg.Install.WhenRunning)
+	if err != nil {
+		return ui.Options{}, err
+	}
+
+	var layout string
+	if cfg.IsSet("install.layout") {
+		layout = cfg.Install.Layout
+	}
+
+	return ui.Options{
+		ConfigureSettings: cfg.Settings.Configure,
+		ExtensionsFile:    cfg.Settings.Extensions,
+		ProfileFile:       cfg.Settings.Profile,
+		Users:             users,
+		RunningPolicy:     runningPolicy,
+		Layout:            layout,
+		Mirror:            cfg.Download.Mirror,
+		HTTP:              httpOptions(),
+		CacheRetention:    cfg.Cache.Retention,
+		Hooks:             hooks(),
+		ChangelogSource:   cfg.Download.Changelog,
+	}, nil
+}
+
+func signalContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+}
+
+func newExtensionsCmd() *cobra.Command {
+	var prune bool
+
+	cmd := &cobra.Command{
+		Use:   "extensions <file>",
+		Short: "Install or remove extensions from a list file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			users, err := targetUsers()
+			if err != nil {
+				return err
+			}
+
+			lock, err := acquireLock(cmd)
+			if err != nil {
+				return err
+			}
+			defer lock.Release()
+
+			ctx, stop := signalContext(cmd)
+			defer stop()
+
+			program := tea.NewProgram(ui.NewExtensionsModel(ctx, args[0], users, prune), tea.WithoutSignalHandler())
+			if _, err := program.Run(); err != nil {
+				return fmt.Errorf("extension provisioning failed: %v", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&prune, "prune", false, "Also uninstall extensions that are not in the list")
+
+	return cmd
+}
+
+func newMigrateCmd() *cobra.Command {
+	var from string
+	var overwrite bool
+	var assumeYes bool
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Import settings, keybindings and extensions from VS Code",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, err := resolveSingleUser()
+			if err != nil {
+				return err
+			}
 
-	return rootCmd.Execute()
+			installer := app.NewInstaller(false, false, false).ForUser(target)
+			plan, err := installer.PlanMigration(from, overwrite)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(ui.NewMigrationPreview(plan).View())
+			if plan.IsEmpty() {
+				return nil
+			}
+
+			if !assumeYes && !confirm("Import this configuration into Cursor?") {
+				fmt.Println("Migration cancelled")
+				return nil
+			}
+
+			lock, err := acquireLock(cmd)
+			if err != nil {
+				return err
+			}
+			defer lock.Release()
+
+			ctx, stop := signalContext(cmd)
+			defer stop()
+
+			program := tea.NewProgram(ui.NewMigrateModel(ctx, installer, plan), tea.WithoutSignalHandler())
+			if _, err := program.Run(); err != nil {
+				return fmt.Errorf("migration failed: %v", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "vscode", fmt.Sprintf("Editor to import from (%s)", strings.Join(app.MigrationSources(), "|")))
+	cmd.Flags().BoolVar(&overwrite, "overwrite", false, "Replace settings and snippets that already exist in Cursor")
+	cmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Import without asking for confirmation")
+
+	return cmd
+}
+
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+func resolveSingleUser() (app.TargetUser, error) {
+	if cfg.Install.Users == "all" {
+		return app.TargetUser{}, fmt.Errorf("--all-users is not supported by this command, use --for-user instead")
+	}
+
+	users, err := targetUsers()
+	if err != nil {
+		return app.TargetUser{}, err
+	}
+	return users[0], nil
 }
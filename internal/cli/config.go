@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/lutefd/cursor-installer/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the installer configuration",
+	}
+
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Print the effective configuration and where each value came from",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fmt.Println(ui.ConfigView(cfg.Fields()))
+			return nil
+		},
+	}
+
+	cmd.AddCommand(showCmd)
+
+	return cmd
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lutefd/cursor-installer/internal/app"
+	"github.com/lutefd/cursor-installer/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func newConfigureCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "configure",
+		Short: "Edit Cursor settings or manage settings profiles",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, err := resolveSingleUser()
+			if err != nil {
+				return err
+			}
+
+			lock, err := acquireLock(cmd)
+			if err != nil {
+				return err
+			}
+			defer lock.Release()
+
+			program := tea.NewProgram(ui.NewSettingsEditor(target))
+			if _, err := program.Run(); err != nil {
+				return fmt.Errorf("settings editor failed: %v", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.AddCommand(newConfigureExportCmd())
+	cmd.AddCommand(newConfigureApplyCmd())
+
+	return cmd
+}
+
+func newConfigureExportCmd() *cobra.Command {
+	var output string
+	var cursorOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export settings, keybindings and extensions as a profile",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, err := resolveSingleUser()
+			if err != nil {
+				return err
+			}
+
+			installer := app.NewInstaller(false, false, false).ForUser(target)
+			profile, err := installer.ExportProfile(cursorOnly)
+			if err != nil {
+				return err
+			}
+
+			if err := app.WriteProfile(output, profile); err != nil {
+				return err
+			}
+
+			fmt.Printf("Exported %d settings, %d keybindings and %d extensions to %s\n",
+				len(profile.Settings), len(profile.Keybindings), len(profile.Extensions), output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "cursor-profile.json", "Path of the profile file to write")
+	cmd.Flags().BoolVar(&cursorOnly, "cursor-only", false, "Only export cursor.* settings")
+
+	return cmd
+}
+
+func newConfigureApplyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "apply <profile>",
+		Short: "Apply an exported profile to this machine",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			users, err := targetUsers()
+			if err != nil {
+				return err
+			}
+
+			lock, err := acquireLock(cmd)
+			if err != nil {
+				return err
+			}
+			defer lock.Release()
+
+			ctx, stop := signalContext(cmd)
+			defer stop()
+
+			program := tea.NewProgram(ui.NewProfileModel(ctx, args[0], users), tea.WithoutSignalHandler())
+			if _, err := program.Run(); err != nil {
+				return fmt.Errorf("applying profile failed: %v", err)
+			}
+			return nil
+		},
+	}
+}
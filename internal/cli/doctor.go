@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/lutefd/cursor-installer/internal/app"
+	"github.com/lutefd/cursor-installer/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func newDoctorCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose problems with the Cursor installation",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			results := app.NewInstaller(false, false, false).RunDiagnostics(cmd.Context())
+			fmt.Println(ui.NewDoctorReport(results).View())
+
+			failed := 0
+			for _, result := range results {
+				if result.Status == app.CheckFail {
+					failed++
+				}
+			}
+			if failed > 0 {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%d checks failed", failed)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lutefd/cursor-installer/internal/app"
+	"github.com/lutefd/cursor-installer/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	cfg            *config.Config
+	mirror         string
+	downloadURL    string
+	httpTimeout    time.Duration
+	connectTimeout time.Duration
+	userAgent      string
+	proxy          string
+	noProxy        string
+	caBundle       string
+	headers        []string
+	changelog      string
+)
+
+var flagKeys = map[string]string{
+	"for-user":         "install.users",
+	"when-running":     "install.when_running",
+	"mirror":           "download.mirror",
+	"download-url":     "download.url",
+	"timeout":          "download.timeout",
+	"connect-timeout":  "download.connect_timeout",
+	"user-agent":       "download.user_agent",
+	"proxy":            "download.proxy",
+	"no-proxy":         "download.no_proxy",
+	"ca-bundle":        "download.ca_bundle",
+	"changelog-source": "download.changelog",
+	"config":           "settings.configure",
+	"profile":          "settings.profile",
+	"extensions":       "settings.extensions",
+}
+
+func addDownloadFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&mirror, "mirror", "", "Download Cursor from a cursor-installer mirror instead of the official endpoint")
+	flags.StringVar(&downloadURL, "download-url", "", "URL to download the Cursor AppImage from")
+	flags.DurationVar(&httpTimeout, "timeout", 0, "Overall timeout for each download (0 disables)")
+	flags.DurationVar(&connectTimeout, "connect-timeout", 0, "Timeout for connecting and the TLS handshake (default 30s)")
+	flags.StringVar(&userAgent, "user-agent", "", "User-Agent header sent with downloads")
+	flags.StringVar(&proxy, "proxy", "", "Proxy URL for downloads, overriding HTTPS_PROXY and HTTP_PROXY")
+	flags.StringVar(&noProxy, "no-proxy", "", "Comma-separated hosts to reach without the proxy, overriding NO_PROXY")
+	flags.StringVar(&caBundle, "ca-bundle", "", "PEM file with additional CA certificates to trust")
+	flags.StringArrayVar(&headers, "header", nil, "Extra request header as \"Name: value\" (repeatable)")
+	flags.StringVar(&changelog, "changelog-source", "", "URL or file with Cursor release notes (defaults to the mirror or cursor.com)")
+}
+
+func loadConfig(cmd *cobra.Command, args []string) error {
+	var path string
+	if invoking, err := app.InvokingUser(); err == nil && invoking.HomeDir != "" {
+		path = config.UserPath(invoking.HomeDir)
+	}
+
+	loaded, err := config.Load(path)
+	if err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
+	if ignored := loaded.Ignored(); len(ignored) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring %s from %s, it is not owned by root\n", strings.Join(ignored, ", "), path)
+	}
+
+	flags := cmd.Flags()
+	if flags.Changed("for-user") && flags.Changed("all-users") {
+		return fmt.Errorf("--for-user and --all-users cannot be used together")
+	}
+	for name, key := range flagKeys {
+		if !flags.Changed(name) {
+			continue
+		}
+		if err := loaded.Set(key, flags.Lookup(name).Value.String(), "flag --"+name); err != nil {
+			return fmt.Errorf("invalid --%s: %v", name, err)
+		}
+	}
+	if flags.Changed("all-users") && allUsers {
+		loaded.Set("install.users", "all", "flag --all-users")
+	}
+	if flags.Changed("extract") {
+		layout := app.LayoutAppImage
+		if extract {
+			layout = app.LayoutExtracted
+		}
+		loaded.Set("install.layout", layout, "flag --extract")
+	}
+	for _, header := range headers {
+		if err := loaded.AddHeader(header); err != nil {
+			return err
+		}
+		loaded.MarkSource("download.headers", "flag --header")
+	}
+	if err := loaded.Validate(); err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
+
+	app.SetPaths(app.Paths{
+		InstallDir:   loaded.Paths.InstallDir,
+		Symlink:      loaded.Paths.Symlink,
+		DesktopEntry: loaded.Paths.DesktopEntry,
+		CacheDir:     loaded.Paths.CacheDir,
+	})
+
+	cfg = loaded
+	return nil
+}
+
+func configureDownloads(installer *app.Installer) {
+	installer.SetMirror(cfg.Download.Mirror)
+	installer.SetHTTPOptions(httpOptions())
+	installer.SetCacheRetention(cfg.Cache.Retention)
+	installer.SetChangelogSource(cfg.Download.Changelog)
+}
+
+func targetUsers() ([]app.TargetUser, error) {
+	if cfg.Install.Users == "all" {
+		return app.ResolveTargetUsers("", true)
+	}
+	return app.ResolveTargetUsers(cfg.Install.Users, false)
+}
+
+func httpOptions() app.HTTPOptions {
+	return app.HTTPOptions{
+		DownloadURL:    cfg.Download.URL,
+		Timeout:        cfg.Download.Timeout,
+		ConnectTimeout: cfg.Download.ConnectTimeout,
+		UserAgent:      cfg.Download.UserAgent,
+		Proxy:          cfg.Download.Proxy,
+		NoProxy:        cfg.Download.NoProxy,
+		CABundle:       cfg.Download.CABundle,
+		Headers:        cfg.Download.Headers,
+	}
+}
+
+func hooks() map[app.HookPoint]string {
+	return map[app.HookPoint]string{
+		app.HookPreDownload: cfg.Hooks.PreDownload,
+		app.HookPreInstall:  cfg.Hooks.PreInstall,
+		app.HookPostInstall: cfg.Hooks.PostInstall,
+		app.HookPostUpdate:  cfg.Hooks.PostUpdate,
+	}
+}
@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/lutefd/cursor-installer/internal/app"
+	"github.com/spf13/cobra"
+)
+
+var waitForLock bool
+
+func acquireLock(cmd *cobra.Command) (*app.Lock, error) {
+	lock, err := app.AcquireLock(cmd.Context(), false)
+
+	var lockedErr *app.LockedError
+	if errors.As(err, &lockedErr) {
+		cmd.SilenceUsage = true
+		if !waitForLock {
+			return nil, fmt.Errorf("%v, use --wait to wait for it to finish", lockedErr)
+		}
+
+		fmt.Fprintf(os.Stderr, "%v, waiting for it to finish...\n", lockedErr)
+		ctx, stop := signalContext(cmd)
+		defer stop()
+		lock, err = app.AcquireLock(ctx, true)
+	}
+	return lock, err
+}
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/lutefd/cursor-installer/internal/app"
+	"github.com/lutefd/cursor-installer/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	verbose bool
+	runLog  *app.RunLog
+)
+
+func openRunLog(cmd *cobra.Command) {
+	if cmd.Name() == "logs" || (cmd.Parent() != nil && cmd.Parent().Name() == "logs") {
+		return
+	}
+
+	target, err := app.InvokingUser()
+	if err != nil {
+		return
+	}
+
+	var stream *os.File
+	if verbose {
+		stream = os.Stderr
+	}
+	runLog, err = app.OpenRunLog(target, cmd.CommandPath(), redactArgs(os.Args[1:]), stream)
+	if err != nil && verbose {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+func redactArgs(args []string) []string {
+	redacted := make([]string, len(args))
+	copy(redacted, args)
+	for idx, arg := range redacted {
+		if value, ok := strings.CutPrefix(arg, "--header="); ok {
+			redacted[idx] = "--header=" + redactHeader(value)
+		} else if arg == "--header" && idx+1 < len(redacted) {
+			redacted[idx+1] = redactHeader(redacted[idx+1])
+		} else if name, value, ok := strings.Cut(arg, "="); ok && strings.HasPrefix(name, "--") {
+			redacted[idx] = name + "=" + redactURL(value)
+		} else {
+			redacted[idx] = redactURL(arg)
+		}
+	}
+	return redacted
+}
+
+func redactHeader(header string) string {
+	name, _, _ := strings.Cut(header, ":")
+	return name + ": <redacted>"
+}
+
+func redactURL(value string) string {
+	if parsed, err := url.Parse(value); err == nil && parsed.User != nil {
+		return parsed.Redacted()
+	}
+	return value
+}
+
+func closeRunLog(err error) {
+	if runLog != nil {
+		runLog.Close(err)
+	}
+}
+
+func newLogsCmd() *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "List recent installer runs and their logs",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, err := app.InvokingUser()
+			if err != nil {
+				return err
+			}
+			runs, err := app.RunLogs(target)
+			if err != nil {
+				return err
+			}
+			if limit > 0 && len(runs) > limit {
+				runs = runs[:limit]
+			}
+			fmt.Println(ui.RunLogsView(app.RunLogDir(target), runs))
+			return nil
+		},
+	}
+	cmd.Flags().IntVarP(&limit, "limit", "n", 10, "Number of runs to list (0 lists all)")
+
+	var raw bool
+	showCmd := &cobra.Command{
+		Use:   "show [run]",
+		Short: "Print the log of a run, by its number in the logs list or path (defaults to the latest)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, err := app.InvokingUser()
+			if err != nil {
+				return err
+			}
+			runs, err := app.RunLogs(target)
+			if err != nil {
+				return err
+			}
+
+			path, err := selectRunLog(runs, args)
+			if err != nil {
+				return err
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read run log: %v", err)
+			}
+
+			if raw {
+				fmt.Print(string(data))
+				return nil
+			}
+			fmt.Print(ui.RunLogView(path, data))
+			return nil
+		},
+	}
+	showCmd.Flags().BoolVar(&raw, "json", false, "Print the raw JSON lines")
+
+	cmd.AddCommand(showCmd)
+
+	return cmd
+}
+
+func selectRunLog(runs []app.RunSummary, args []string) (string, error) {
+	if len(args) == 0 {
+		if len(runs) == 0 {
+			return "", fmt.Errorf("no installer runs have been logged yet")
+		}
+		return runs[0].Path, nil
+	}
+
+	if index, err := strconv.Atoi(args[0]); err == nil {
+		if index < 1 || index > len(runs) {
+			return "", fmt.Errorf("run %d not found, there are %d logged runs", index, len(runs))
+		}
+		return runs[index-1].Path, nil
+	}
+	return args[0], nil
+}
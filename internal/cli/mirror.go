@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lutefd/cursor-installer/internal/app"
+	"github.com/spf13/cobra"
+)
+
+func newMirrorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mirror",
+		Short: "Share cached Cursor downloads with other machines",
+	}
+
+	var listen string
+	var refresh time.Duration
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the download cache over HTTP, mimicking the Cursor download endpoint",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			installer := app.NewInstaller(false, false, false)
+			configureDownloads(installer)
+
+			dir, err := installer.CacheDir()
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Serving %s on http://%s/linux/appImage/x64\n", dir, listen)
+			ctx, stop := signalContext(cmd)
+			defer stop()
+
+			return installer.ServeMirror(ctx, listen, refresh, os.Stdout)
+		},
+	}
+	serveCmd.Flags().StringVar(&listen, "listen", ":8080", "Address to listen on")
+	serveCmd.Flags().DurationVar(&refresh, "refresh", 6*time.Hour, "How often to pull the latest AppImage into the cache (0 disables)")
+
+	cmd.AddCommand(serveCmd)
+
+	return cmd
+}
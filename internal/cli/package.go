@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lutefd/cursor-installer/internal/app"
+	"github.com/lutefd/cursor-installer/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func newPackageCmd() *cobra.Command {
+	var format string
+	var appImagePath string
+	var outputDir string
+	var maintainer string
+
+	cmd := &cobra.Command{
+		Use:   "package",
+		Short: "Build a deb, rpm or tar package from the Cursor AppImage",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			packageFormat, err := app.ParsePackageFormat(format)
+			if err != nil {
+				return err
+			}
+
+			lock, err := acquireLock(cmd)
+			if err != nil {
+				return err
+			}
+			defer lock.Release()
+
+			installer := app.NewInstaller(false, false, false)
+			configureDownloads(installer)
+
+			ctx, stop := signalContext(cmd)
+			defer stop()
+
+			program := tea.NewProgram(ui.NewPackageModel(ctx, installer, packageFormat, appImagePath, outputDir, maintainer), tea.WithoutSignalHandler())
+			if _, err := program.Run(); err != nil {
+				return fmt.Errorf("packaging failed: %v", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", string(app.PackageDeb), "Package format (deb|rpm|tar)")
+	cmd.Flags().StringVar(&appImagePath, "appimage", "", "Package a local AppImage instead of downloading the latest one")
+	cmd.Flags().StringVarP(&outputDir, "output", "o", ".", "Directory to write the package to")
+	cmd.Flags().StringVar(&maintainer, "maintainer", "cursor-installer <root@localhost>", "Maintainer recorded in the package metadata")
+
+	return cmd
+}
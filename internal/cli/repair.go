@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lutefd/cursor-installer/internal/app"
+	"github.com/lutefd/cursor-installer/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func newRepairCmd() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "repair",
+		Short: "Restore missing or drifted pieces of the installation without downloading",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			lock, err := acquireLock(cmd)
+			if err != nil {
+				return err
+			}
+			defer lock.Release()
+
+			installer := app.NewInstaller(false, false, false)
+			configureDownloads(installer)
+			actions, err := installer.PlanRepair(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(ui.RepairPlanView(actions))
+			if len(actions) == 0 || dryRun {
+				return nil
+			}
+
+			ctx, stop := signalContext(cmd)
+			defer stop()
+
+			program := tea.NewProgram(ui.NewRepairModel(ctx, installer, actions), tea.WithoutSignalHandler())
+			if _, err := program.Run(); err != nil {
+				return fmt.Errorf("repair failed: %v", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only show what would be repaired")
+
+	return cmd
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lutefd/cursor-installer/internal/app"
+	"github.com/lutefd/cursor-installer/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func newSelfUpdateCmd() *cobra.Command {
+	var check bool
+	var force bool
+	var releaseURL string
+
+	cmd := &cobra.Command{
+		Use:   "self-update",
+		Short: "Update cursor-installer itself to the latest release",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signalContext(cmd)
+			defer stop()
+
+			installer := releaseInstaller()
+			release, err := installer.LatestInstallerRelease(ctx, releaseURL)
+			if err != nil {
+				return err
+			}
+
+			if check || (!release.Newer() && !force) {
+				fmt.Println(ui.InstallerReleaseView(release))
+				return nil
+			}
+
+			lock, err := acquireLock(cmd)
+			if err != nil {
+				return err
+			}
+			defer lock.Release()
+
+			program := tea.NewProgram(ui.NewSelfUpdateModel(ctx, installer, release), tea.WithoutSignalHandler())
+			if _, err := program.Run(); err != nil {
+				return fmt.Errorf("self-update failed: %v", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&check, "check", false, "Only report whether a newer release is available")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Reinstall the latest release even if it is not newer")
+	cmd.Flags().StringVar(&releaseURL, "release-url", "", "GitHub releases API URL to read the latest release from")
+
+	return cmd
+}
+
+// releaseInstaller keeps the proxy and TLS settings but not the custom
+// headers, which are meant for the Cursor download server or mirror.
+func releaseInstaller() *app.Installer {
+	installer := app.NewInstaller(false, false, false)
+	opts := httpOptions()
+	opts.Headers = nil
+	installer.SetHTTPOptions(opts)
+	return installer
+}
+
+func installerUpdate(ctx context.Context) string {
+	ctx, cancel := context.WithTimeout(ctx, versionCheckTimeout)
+	defer cancel()
+
+	release, err := releaseInstaller().LatestInstallerRelease(ctx, "")
+	if err != nil || !release.Newer() {
+		return ""
+	}
+	return release.Version
+}
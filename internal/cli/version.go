@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lutefd/cursor-installer/internal/app"
+)
+
+const versionCheckTimeout = 3 * time.Second
+
+func versionInfo(ctx context.Context, checkLatest bool) (*app.VersionInfo, error) {
+	installer := app.NewInstaller(false, false, false)
+	configureDownloads(installer)
+
+	info, err := installer.GetVersionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	info.Channel = cfg.Install.Channel
+	info.LatestChecked = checkLatest
+	if !checkLatest {
+		return info, nil
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		info.InstallerUpdate = installerUpdate(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		checkCtx, cancel := context.WithTimeout(ctx, versionCheckTimeout)
+		defer cancel()
+		if latest, err := installer.LatestVersion(checkCtx); err == nil {
+			info.LatestVersion = latest
+		}
+	}()
+	wg.Wait()
+
+	return info, nil
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lutefd/cursor-installer/internal/app"
+	"github.com/lutefd/cursor-installer/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func newVersionsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "versions",
+		Short: "Pick a Cursor version to install or roll back to",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			installer := app.NewInstaller(false, false, false)
+			configureDownloads(installer)
+
+			ctx, stop := signalContext(cmd)
+			defer stop()
+
+			versions, err := installer.AvailableVersions(ctx)
+			if err != nil {
+				return err
+			}
+			for _, warning := range installer.Report().Warnings {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+			}
+			if len(versions) == 0 {
+				return fmt.Errorf("no Cursor versions found in the cache, on a mirror or at the download endpoint")
+			}
+
+			final, err := tea.NewProgram(ui.NewVersionPicker(versions), tea.WithAltScreen(), tea.WithoutSignalHandler()).Run()
+			if err != nil {
+				return fmt.Errorf("version picker failed: %v", err)
+			}
+			version := final.(ui.VersionPicker).Selected()
+			if version == "" {
+				return nil
+			}
+
+			lock, err := acquireLock(cmd)
+			if err != nil {
+				return err
+			}
+			defer lock.Release()
+
+			opts, err := installOptions()
+			if err != nil {
+				return err
+			}
+			opts.Version = version
+
+			program := tea.NewProgram(ui.NewModel(ctx, opts), tea.WithoutSignalHandler())
+			if _, err := program.Run(); err != nil {
+				return fmt.Errorf("installation failed: %v", err)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,387 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	SystemPath = "/etc/cursor-installer/config.yaml"
+	envPrefix  = "CURSOR_INSTALLER_"
+)
+
+type Config struct {
+	Install  Install  `yaml:"install"`
+	Paths    Paths    `yaml:"paths"`
+	Download Download `yaml:"download"`
+	Cache    Cache    `yaml:"cache"`
+	Settings Settings `yaml:"settings"`
+	Hooks    Hooks    `yaml:"hooks"`
+
+	sources map[string]string
+	ignored []string
+}
+
+type Install struct {
+	Users       string `yaml:"users" env:"USERS"`
+	Layout      string `yaml:"layout" env:"LAYOUT"`
+	Channel     string `yaml:"channel" env:"CHANNEL"`
+	WhenRunning string `yaml:"when_running" env:"WHEN_RUNNING"`
+}
+
+type Paths struct {
+	InstallDir   string `yaml:"install_dir" env:"INSTALL_DIR"`
+	Symlink      string `yaml:"symlink" env:"SYMLINK"`
+	DesktopEntry string `yaml:"desktop_entry" env:"DESKTOP_ENTRY"`
+	CacheDir     string `yaml:"cache_dir" env:"CACHE_DIR"`
+}
+
+type Download struct {
+	URL            string            `yaml:"url" env:"DOWNLOAD_URL"`
+	Mirror         string            `yaml:"mirror" env:"MIRROR"`
+	Timeout        time.Duration     `yaml:"timeout" env:"TIMEOUT"`
+	ConnectTimeout time.Duration     `yaml:"connect_timeout" env:"CONNECT_TIMEOUT"`
+	UserAgent      string            `yaml:"user_agent" env:"USER_AGENT"`
+	Proxy          string            `yaml:"proxy" env:"PROXY"`
+	NoProxy        string            `yaml:"no_proxy" env:"NO_PROXY"`
+	CABundle       string            `yaml:"ca_bundle" env:"CA_BUNDLE"`
+	Headers        map[string]string `yaml:"headers" env:"HEADERS"`
+	Changelog      string            `yaml:"changelog" env:"CHANGELOG"`
+}
+
+type Cache struct {
+	Retention int `yaml:"retention" env:"CACHE_RETENTION"`
+}
+
+type Settings struct {
+	Configure  bool   `yaml:"configure" env:"CONFIGURE"`
+	Profile    string `yaml:"profile" env:"PROFILE"`
+	Extensions string `yaml:"extensions" env:"EXTENSIONS"`
+}
+
+type Hooks struct {
+	PreDownload  string `yaml:"pre_download" env:"HOOK_PRE_DOWNLOAD"`
+	PreInstall   string `yaml:"pre_install" env:"HOOK_PRE_INSTALL"`
+	PostInstall  string `yaml:"post_install" env:"HOOK_POST_INSTALL"`
+	PostUpdate   string `yaml:"post_update" env:"HOOK_POST_UPDATE"`
+	PreUninstall string `yaml:"pre_uninstall" env:"HOOK_PRE_UNINSTALL"`
+}
+
+type Field struct {
+	Key    string
+	Value  string
+	Source string
+}
+
+func Default() *Config {
+	return &Config{
+		Install: Install{
+			Layout:  "appimage",
+			Channel: "stable",
+		},
+		Paths: Paths{
+			InstallDir:   "/opt/cursor",
+			Symlink:      "/usr/local/bin/cursor",
+			DesktopEntry: "/usr/share/applications/cursor.desktop",
+		},
+		Download: Download{
+			URL:            "https://downloader.cursor.sh/linux/appImage/x64",
+			ConnectTimeout: 30 * time.Second,
+		},
+		sources: make(map[string]string),
+	}
+}
+
+func UserPath(homeDir string) string {
+	return filepath.Join(homeDir, ".config", "cursor-installer", "config.yaml")
+}
+
+func Load(userPath string) (*Config, error) {
+	cfg := Default()
+
+	if err := cfg.loadFile("system", SystemPath); err != nil {
+		return nil, err
+	}
+	if userPath != "" {
+		paths, hooks, sources := cfg.Paths, cfg.Hooks, maps.Clone(cfg.sources)
+		if err := cfg.loadFile("user", userPath); err != nil {
+			return nil, err
+		}
+		// Under sudo the user file is still writable by the invoking user, so
+		// it must not choose what root deletes, installs over or executes.
+		if os.Geteuid() == 0 && !rootOwned(userPath) {
+			cfg.Paths, cfg.Hooks = paths, hooks
+			cfg.restoreSources(sources, "paths.", "hooks.")
+		}
+	}
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+
+	return cfg, cfg.Validate()
+}
+
+func (c *Config) loadFile(layer, path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", path, err)
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	if len(document.Content) == 0 {
+		return nil
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil {
+		return fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+
+	root := document.Content[0]
+	for idx := 0; idx+1 < len(root.Content); idx += 2 {
+		section := root.Content[idx+1]
+		for field := 0; field+1 < len(section.Content); field += 2 {
+			key := root.Content[idx].Value + "." + section.Content[field].Value
+			c.sources[key] = fmt.Sprintf("%s (%s)", layer, path)
+		}
+	}
+	return nil
+}
+
+func (c *Config) restoreSources(previous map[string]string, prefixes ...string) {
+	for key, source := range c.sources {
+		if source == previous[key] {
+			continue
+		}
+		for _, prefix := range prefixes {
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+			if previous[key] == "" {
+				delete(c.sources, key)
+			} else {
+				c.sources[key] = previous[key]
+			}
+			c.ignored = append(c.ignored, key)
+		}
+	}
+	sort.Strings(c.ignored)
+}
+
+// Ignored lists the keys dropped from the user file because it is not owned by
+// root while running as root.
+func (c *Config) Ignored() []string {
+	return c.ignored
+}
+
+func rootOwned(path string) bool {
+	for _, current := range []string{path, filepath.Dir(path)} {
+		info, err := os.Stat(current)
+		if os.IsNotExist(err) && current == path {
+			continue
+		}
+		if err != nil {
+			return false
+		}
+		stat, ok := info.Sys().(*syscall.Stat_t)
+		if !ok || stat.Uid != 0 || info.Mode().Perm()&0022 != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Config) applyEnv() error {
+	for _, field := range c.fields() {
+		value, ok := os.LookupEnv(envPrefix + field.env)
+		if !ok {
+			continue
+		}
+		if err := c.Set(field.key, value, "env "+envPrefix+field.env); err != nil {
+			return fmt.Errorf("invalid %s%s: %v", envPrefix, field.env, err)
+		}
+	}
+	return nil
+}
+
+func (c *Config) Validate() error {
+	switch c.Install.Layout {
+	case "appimage", "extracted":
+	default:
+		return fmt.Errorf("install.layout must be appimage or extracted, got %q", c.Install.Layout)
+	}
+	if c.Install.Channel != "stable" {
+		return fmt.Errorf("install.channel %q is not available, the download endpoint only publishes the stable channel", c.Install.Channel)
+	}
+	if c.Hooks.PreUninstall != "" {
+		return fmt.Errorf("hooks.pre_uninstall is not supported yet, the installer has no uninstall command")
+	}
+	if c.Cache.Retention < 0 {
+		return fmt.Errorf("cache.retention must not be negative")
+	}
+	for key, path := range map[string]string{
+		"paths.install_dir":   c.Paths.InstallDir,
+		"paths.symlink":       c.Paths.Symlink,
+		"paths.desktop_entry": c.Paths.DesktopEntry,
+		"paths.cache_dir":     c.Paths.CacheDir,
+	} {
+		if path != "" && !filepath.IsAbs(path) {
+			return fmt.Errorf("%s must be an absolute path, got %q", key, path)
+		}
+	}
+	if !strings.Contains(strings.ToLower(filepath.Base(c.Paths.InstallDir)), "cursor") {
+		return fmt.Errorf("paths.install_dir must be a directory dedicated to Cursor such as /opt/cursor, got %q", c.Paths.InstallDir)
+	}
+	return nil
+}
+
+func (c *Config) Set(key, value, source string) error {
+	for _, field := range c.fields() {
+		if field.key != key {
+			continue
+		}
+
+		switch target := field.value.Addr().Interface().(type) {
+		case *string:
+			*target = value
+		case *bool:
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				return err
+			}
+			*target = parsed
+		case *int:
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				return err
+			}
+			*target = parsed
+		case *time.Duration:
+			parsed, err := time.ParseDuration(value)
+			if err != nil {
+				return err
+			}
+			*target = parsed
+		case *map[string]string:
+			for _, header := range strings.Split(value, ";") {
+				if strings.TrimSpace(header) == "" {
+					continue
+				}
+				if err := c.AddHeader(header); err != nil {
+					return err
+				}
+			}
+		}
+
+		c.sources[key] = source
+		return nil
+	}
+	return fmt.Errorf("unknown config key %q", key)
+}
+
+func (c *Config) AddHeader(header string) error {
+	key, value, ok := strings.Cut(header, ":")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return fmt.Errorf("header %q must be in the form \"Name: value\"", header)
+	}
+	if c.Download.Headers == nil {
+		c.Download.Headers = make(map[string]string)
+	}
+	c.Download.Headers[key] = strings.TrimSpace(value)
+	return nil
+}
+
+func (c *Config) MarkSource(key, source string) {
+	c.sources[key] = source
+}
+
+func (c *Config) IsSet(key string) bool {
+	return c.sources[key] != ""
+}
+
+func (c *Config) Fields() []Field {
+	var fields []Field
+	for _, field := range c.fields() {
+		source := c.sources[field.key]
+		if source == "" {
+			source = "default"
+		}
+		fields = append(fields, Field{Key: field.key, Value: formatValue(field.value), Source: source})
+	}
+	return fields
+}
+
+type configField struct {
+	key   string
+	env   string
+	value reflect.Value
+}
+
+func (c *Config) fields() []configField {
+	var fields []configField
+
+	root := reflect.ValueOf(c).Elem()
+	for section := 0; section < root.NumField(); section++ {
+		sectionType := root.Type().Field(section)
+		sectionName := sectionType.Tag.Get("yaml")
+		if sectionName == "" {
+			continue
+		}
+
+		value := root.Field(section)
+		for field := 0; field < value.NumField(); field++ {
+			fieldType := value.Type().Field(field)
+			fields = append(fields, configField{
+				key:   sectionName + "." + fieldType.Tag.Get("yaml"),
+				env:   fieldType.Tag.Get("env"),
+				value: value.Field(field),
+			})
+		}
+	}
+
+	return fields
+}
+
+func formatValue(value reflect.Value) string {
+	switch v := value.Interface().(type) {
+	case map[string]string:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key+": <redacted>")
+		}
+		sort.Strings(keys)
+		return strings.Join(keys, ", ")
+	case string:
+		if parsed, err := url.Parse(v); err == nil && parsed.User != nil {
+			return parsed.Redacted()
+		}
+		return v
+	case time.Duration:
+		if v == 0 {
+			return "0"
+		}
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lutefd/cursor-installer/internal/app"
+)
+
+type AutoUpdateStatusDisplay struct {
+	status *app.AutoUpdateStatus
+}
+
+func NewAutoUpdateStatusDisplay(status *app.AutoUpdateStatus) *AutoUpdateStatusDisplay {
+	return &AutoUpdateStatusDisplay{status: status}
+}
+
+func (d *AutoUpdateStatusDisplay) View() string {
+	var s strings.Builder
+
+	s.WriteString(versionHeaderStyle.Render(fmt.Sprintf("Automatic Updates (%s)", d.status.Scope)) + "\n\n")
+
+	if !d.status.Installed {
+		s.WriteString(styleStepMessage.Render("  Automatic updates are not enabled. Run `cursor-installer auto-update enable` to set them up.") + "\n")
+		return s.String()
+	}
+
+	header := []string{
+		tableHeaderStyle.Render("Property"),
+		tableHeaderStyle.Render("Value"),
+	}
+
+	data := [][]string{
+		{tableRowStyle.Render("Enabled"), tableValueStyle.Render(valueOrUnknown(d.status.Enabled))},
+		{tableRowStyle.Render("Active"), tableValueStyle.Render(valueOrUnknown(d.status.Active))},
+		{tableRowStyle.Render("Schedule"), tableValueStyle.Render(valueOrUnknown(d.status.Schedule))},
+		{tableRowStyle.Render("Next run"), tableValueStyle.Render(valueOrUnknown(d.status.NextRun))},
+		{tableRowStyle.Render("Last run"), tableValueStyle.Render(valueOrUnknown(d.status.LastRun))},
+		{tableRowStyle.Render("Log file"), tableValueStyle.Render(d.status.LogPath)},
+	}
+
+	s.WriteString(renderTable(header, data))
+
+	if len(d.status.RecentLog) > 0 {
+		s.WriteString("\n" + tableHeaderStyle.Render("Recent runs") + "\n")
+		for _, line := range d.status.RecentLog {
+			s.WriteString("  " + styleStepMessage.Render(line) + "\n")
+		}
+	}
+
+	return s.String()
+}
+
+func valueOrUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lutefd/cursor-installer/internal/app"
+)
+
+func CacheListView(dir string, entries []app.CacheEntry) string {
+	var s strings.Builder
+
+	s.WriteString(versionHeaderStyle.Render("Download Cache") + "\n\n")
+	s.WriteString(styleStepMessage.Render("  "+dir) + "\n\n")
+
+	if len(entries) == 0 {
+		s.WriteString(styleStepMessage.Render("  The cache is empty.") + "\n")
+		return s.String()
+	}
+
+	header := []string{
+		tableHeaderStyle.Render("Version"),
+		tableHeaderStyle.Render("SHA-256"),
+		tableHeaderStyle.Render("Size"),
+		tableHeaderStyle.Render("Last used"),
+	}
+
+	var total int64
+	var data [][]string
+	for _, entry := range entries {
+		total += entry.Size
+		data = append(data, []string{
+			tableRowStyle.Render(entry.Version),
+			tableValueStyle.Render(entry.Checksum[:12]),
+			tableValueStyle.Render(formatSize(entry.Size)),
+			tableValueStyle.Render(entry.Modified.Format("2006-01-02 15:04")),
+		})
+	}
+
+	s.WriteString(renderTable(header, data))
+	s.WriteString(styleStepMessage.Render(fmt.Sprintf("  %d downloads, %s total", len(entries), formatSize(total))) + "\n")
+
+	return s.String()
+}
+
+func CacheRemovedView(removed []app.CacheEntry) string {
+	if len(removed) == 0 {
+		return styleStepMessage.Render("Nothing to remove from the cache")
+	}
+
+	var total int64
+	for _, entry := range removed {
+		total += entry.Size
+	}
+	return styleSuccess.Render(fmt.Sprintf("Removed %d cached downloads (%s)", len(removed), formatSize(total)))
+}
+
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lutefd/cursor-installer/internal/app"
+)
+
+const notesHeight = 15
+
+var releaseHeadingStyle = lipgloss.NewStyle().
+	Bold(true).
+	Foreground(primaryColor)
+
+func releaseNotesSteps(installer *app.Installer) []InstallationStep {
+	return []InstallationStep{{
+		name:    "Release Notes",
+		message: "Fetching release notes...",
+		run:     installer.CheckReleaseNotes,
+	}}
+}
+
+func renderReleaseNotes(releases []app.ReleaseNotes, width int) string {
+	var b strings.Builder
+	for idx, release := range releases {
+		if idx > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(releaseHeadingStyle.Render(release.Title) + "\n")
+		if release.Notes != "" {
+			b.WriteString(lipgloss.NewStyle().Width(width).Render(release.Notes) + "\n")
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func writeReleaseNotes(out io.Writer, notes *app.ReleaseNotesError) {
+	for _, release := range notes.Releases {
+		fmt.Fprintf(out, "\n## %s\n", release.Title)
+		if release.Notes != "" {
+			fmt.Fprintf(out, "%s\n", release.Notes)
+		}
+	}
+	fmt.Fprintln(out)
+}
+
+func (m model) showReleaseNotes(notes *app.ReleaseNotesError) model {
+	m.releaseNotes = notes
+	m.steps[m.currentStep].message = "Review the release notes below"
+	m.notes = viewport.New(m.details.Width, notesHeight)
+	m.notes.SetContent(renderReleaseNotes(notes.Releases, m.notes.Width))
+	return m
+}
+
+func (m model) handleReleaseNotesChoice(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "i", "enter":
+		m.releaseNotes = nil
+		step := m.steps[m.currentStep]
+		return m, func() tea.Msg {
+			return stepCompleteMsg{stepName: step.name, nextStep: m.currentStep + 1}
+		}
+	case "s":
+		version := m.releaseNotes.To
+		m.releaseNotes = nil
+		m.skipped = version
+		m.installer.Cleanup()
+		return m, tea.Sequence(
+			tea.Println(styleWarning.Render(fmt.Sprintf("Skipped Cursor %s, the installed version was not changed", version))),
+			tea.Quit,
+		)
+	case "q", "esc":
+		m.cancel()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.notes, cmd = m.notes.Update(msg)
+	return m, cmd
+}
+
+func (m model) releaseNotesView() string {
+	notes := m.releaseNotes
+	title := fmt.Sprintf("Cursor %s is available", notes.To)
+	if notes.From != "" {
+		title = fmt.Sprintf("Cursor %s → %s", notes.From, notes.To)
+	}
+
+	var s string
+	s += styleProgress.Render(title) + "\n"
+	s += styleDetails.Render(m.notes.View()) + "\n"
+	s += styleHelp.Render("i install • s skip this update • ↑/↓ scroll • q cancel") + "\n"
+	return s
+}
@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/lutefd/cursor-installer/internal/config"
+)
+
+func ConfigView(fields []config.Field) string {
+	var s strings.Builder
+
+	s.WriteString(versionHeaderStyle.Render("Effective Configuration") + "\n\n")
+
+	header := []string{
+		tableHeaderStyle.Render("Key"),
+		tableHeaderStyle.Render("Value"),
+		tableHeaderStyle.Render("Source"),
+	}
+
+	var data [][]string
+	for _, field := range fields {
+		value := field.Value
+		if value == "" {
+			value = "-"
+		}
+		data = append(data, []string{
+			tableRowStyle.Render(field.Key),
+			tableValueStyle.Render(value),
+			tableValueStyle.Render(field.Source),
+		})
+	}
+
+	s.WriteString(renderTable(header, data))
+
+	return s.String()
+}
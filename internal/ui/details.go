@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	detailsHeight      = 10
+	detailsWidth       = 80
+	failureOutputLines = 20
+)
+
+type stepOutput struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (o *stepOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.buf.Write(p)
+}
+
+func (o *stepOutput) Lines() []string {
+	o.mu.Lock()
+	text := o.buf.String()
+	o.mu.Unlock()
+
+	text = strings.TrimRight(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	if text == "" {
+		return nil
+	}
+
+	lines := strings.Split(text, "\n")
+	for idx, line := range lines {
+		if cr := strings.LastIndex(line, "\r"); cr >= 0 {
+			lines[idx] = line[cr+1:]
+		}
+	}
+	return lines
+}
+
+func newDetails() viewport.Model {
+	return viewport.New(detailsWidth, detailsHeight)
+}
+
+func (m model) detailsStep() int {
+	if m.currentStep >= len(m.steps) {
+		return len(m.steps) - 1
+	}
+	return m.currentStep
+}
+
+func (m model) refreshDetails() model {
+	if !m.showDetails || len(m.outputs) == 0 {
+		return m
+	}
+
+	lines := m.outputs[m.detailsStep()].Lines()
+	content := "No output yet"
+	if len(lines) > 0 {
+		content = strings.Join(lines, "\n")
+	}
+
+	following := m.details.AtBottom()
+	m.details.SetContent(lipgloss.NewStyle().Width(m.details.Width).Render(content))
+	if following {
+		m.details.GotoBottom()
+	}
+	return m
+}
+
+func (m model) detailsView() string {
+	step := m.steps[m.detailsStep()]
+	header := styleStepMessage.Render("Details: " + step.name)
+	return lipgloss.JoinVertical(lipgloss.Left, header, styleDetails.Render(m.details.View()))
+}
+
+func (m model) failureOutput() string {
+	if len(m.outputs) == 0 {
+		return ""
+	}
+
+	lines := m.outputs[m.detailsStep()].Lines()
+	if len(lines) == 0 {
+		return ""
+	}
+	if len(lines) > failureOutputLines {
+		lines = lines[len(lines)-failureOutputLines:]
+	}
+	return styleDetails.Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lutefd/cursor-installer/internal/app"
+)
+
+type DoctorReport struct {
+	results []app.DiagnosticResult
+}
+
+func NewDoctorReport(results []app.DiagnosticResult) *DoctorReport {
+	return &DoctorReport{results: results}
+}
+
+func (d *DoctorReport) View() string {
+	var s strings.Builder
+
+	s.WriteString(versionHeaderStyle.Render("Cursor Doctor") + "\n\n")
+
+	header := []string{
+		tableHeaderStyle.Render("Check"),
+		tableHeaderStyle.Render("Status"),
+		tableHeaderStyle.Render("Details"),
+	}
+
+	var data [][]string
+	for _, result := range d.results {
+		data = append(data, []string{
+			tableRowStyle.Render(result.Name),
+			checkStatusStyle(result.Status).Render(string(result.Status)),
+			tableValueStyle.Render(result.Detail),
+		})
+	}
+
+	s.WriteString(renderTable(header, data))
+
+	var fixes []string
+	for _, result := range d.results {
+		if result.Status != app.CheckPass && result.Fix != "" {
+			fixes = append(fixes, fmt.Sprintf("%s%s: %s", stylePending.String(), result.Name, result.Fix))
+		}
+	}
+
+	if len(fixes) > 0 {
+		s.WriteString("\n" + tableHeaderStyle.Render("Suggested fixes") + "\n")
+		for _, fix := range fixes {
+			s.WriteString("  " + fix + "\n")
+		}
+	} else {
+		s.WriteString("\n" + styleSuccess.Render("✨ Everything looks healthy! ✨") + "\n")
+	}
+
+	return s.String()
+}
+
+func checkStatusStyle(status app.CheckStatus) lipgloss.Style {
+	switch status {
+	case app.CheckPass:
+		return lipgloss.NewStyle().Foreground(successColor).Bold(true)
+	case app.CheckWarn:
+		return lipgloss.NewStyle().Foreground(warningColor).Bold(true)
+	default:
+		return lipgloss.NewStyle().Foreground(errorColor).Bold(true)
+	}
+}
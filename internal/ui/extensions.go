@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lutefd/cursor-installer/internal/app"
+)
+
+func extensionSteps(installer *app.Installer, extensionsFile string) []InstallationStep {
+	entries, err := app.ReadExtensionList(extensionsFile)
+	if err == nil {
+		var changes []app.ExtensionChange
+		changes, err = installer.PlanExtensions(entries)
+		if err == nil {
+			return extensionChangeSteps(installer, changes)
+		}
+	}
+
+	return []InstallationStep{
+		{
+			name:    "Extensions",
+			message: "Reading extension list...",
+			run: func(ctx context.Context) error {
+				return err
+			},
+		},
+	}
+}
+
+func extensionChangeSteps(installer *app.Installer, changes []app.ExtensionChange) []InstallationStep {
+	if len(changes) == 0 {
+		return []InstallationStep{
+			{
+				name:    "Extensions",
+				message: "All listed extensions are already in place",
+				run: func(ctx context.Context) error {
+					return nil
+				},
+			},
+		}
+	}
+
+	steps := make([]InstallationStep, 0, len(changes))
+	for _, change := range changes {
+		change := change
+		var name, message string
+		if change.Action == app.ExtensionRemove {
+			name = fmt.Sprintf("Remove %s", change.ID)
+			message = fmt.Sprintf("Removing extension %s...", change.ID)
+		} else {
+			name = fmt.Sprintf("Install %s", change.ID)
+			message = fmt.Sprintf("Installing extension %s...", change.ID)
+		}
+		steps = append(steps, InstallationStep{
+			name:    name,
+			message: message,
+			run: func(ctx context.Context) error {
+				return installer.ApplyExtensionChange(ctx, change)
+			},
+		})
+	}
+	return steps
+}
+
+func NewExtensionsModel(ctx context.Context, extensionsFile string, users []app.TargetUser, prune bool) model {
+	installer := app.NewInstaller(false, false, false)
+	installer.SetPruneExtensions(prune)
+	steps := userSteps(installer, users, func(installer *app.Installer) []InstallationStep {
+		return extensionSteps(installer, extensionsFile)
+	})
+
+	return model{
+		spinner:        newSpinner(),
+		steps:          steps,
+		completedSteps: make([]bool, len(steps)),
+		installer:      installer,
+		title:          "Cursor Extensions",
+		successMessage: "✨ Cursor extensions are up to date! ✨",
+	}.withContext(ctx)
+}
@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lutefd/cursor-installer/internal/app"
+)
+
+func RunLogsView(dir string, runs []app.RunSummary) string {
+	var s strings.Builder
+
+	s.WriteString(versionHeaderStyle.Render("Installer Runs") + "\n\n")
+	s.WriteString(styleStepMessage.Render("  "+dir) + "\n\n")
+
+	if len(runs) == 0 {
+		s.WriteString(styleStepMessage.Render("  No runs have been logged yet.") + "\n")
+		return s.String()
+	}
+
+	header := []string{
+		tableHeaderStyle.Render("#"),
+		tableHeaderStyle.Render("Started"),
+		tableHeaderStyle.Render("Command"),
+		tableHeaderStyle.Render("Duration"),
+		tableHeaderStyle.Render("Result"),
+	}
+
+	var data [][]string
+	for idx, run := range runs {
+		result := lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("ok")
+		duration := run.Duration.Round(time.Millisecond).String()
+		switch {
+		case !run.Finished:
+			result = lipgloss.NewStyle().Foreground(warningColor).Bold(true).Render("interrupted")
+			duration = "-"
+		case run.Error != "":
+			result = lipgloss.NewStyle().Foreground(errorColor).Bold(true).Render(truncate(run.Error, 60))
+		}
+		data = append(data, []string{
+			tableRowStyle.Render(fmt.Sprint(idx + 1)),
+			tableValueStyle.Render(run.Started.Local().Format("2006-01-02 15:04:05")),
+			tableValueStyle.Render(run.Command),
+			tableValueStyle.Render(duration),
+			result,
+		})
+	}
+
+	s.WriteString(renderTable(header, data))
+	s.WriteString(styleStepMessage.Render("  Use `cursor-installer logs show <#>` to view a run") + "\n")
+
+	return s.String()
+}
+
+func RunLogView(path string, data []byte) string {
+	var s strings.Builder
+
+	s.WriteString(versionHeaderStyle.Render("Run Log") + "\n\n")
+	s.WriteString(styleStepMessage.Render("  "+path) + "\n\n")
+
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			s.WriteString(line + "\n")
+			continue
+		}
+
+		timestamp, _ := time.Parse(time.RFC3339Nano, fmt.Sprint(record["time"]))
+		level := fmt.Sprint(record["level"])
+		message := fmt.Sprint(record["msg"])
+		delete(record, "time")
+		delete(record, "level")
+		delete(record, "msg")
+
+		keys := make([]string, 0, len(record))
+		for key := range record {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var attrs []string
+		for _, key := range keys {
+			attrs = append(attrs, fmt.Sprintf("%s=%s", key, formatLogValue(key, record[key])))
+		}
+
+		levelStyle := styleStepMessage
+		switch level {
+		case "ERROR":
+			levelStyle = lipgloss.NewStyle().Foreground(errorColor).Bold(true)
+		case "WARN":
+			levelStyle = lipgloss.NewStyle().Foreground(warningColor).Bold(true)
+		}
+
+		s.WriteString(fmt.Sprintf("%s %s %s %s\n",
+			styleStepMessage.Render(timestamp.Local().Format("15:04:05.000")),
+			levelStyle.Render(fmt.Sprintf("%-5s", level)),
+			tableValueStyle.Render(message),
+			styleStepMessage.Render(strings.Join(attrs, " "))))
+	}
+
+	return s.String()
+}
+
+func formatLogValue(key string, value interface{}) string {
+	if nanos, ok := value.(float64); ok && key == "duration" {
+		return time.Duration(nanos).Round(time.Millisecond).String()
+	}
+	if text, ok := value.(string); ok {
+		if strings.ContainsAny(text, " \n") {
+			return fmt.Sprintf("%q", text)
+		}
+		return text
+	}
+	var encoded strings.Builder
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Sprint(value)
+	}
+	return strings.TrimSpace(encoded.String())
+}
+
+func truncate(text string, width int) string {
+	if len(text) <= width {
+		return text
+	}
+	return text[:width-1] + "…"
+}
@@ -1,12 +1,16 @@
 package ui
 
+import "time"
+
 type stepCompleteMsg struct {
 	stepName string
 	nextStep int
+	duration time.Duration
 }
 
 type errMsg error
 type doneMsg struct{}
+type cancelMsg struct{}
 type upToDateMsg struct {
 	version string
 }
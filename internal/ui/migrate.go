@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lutefd/cursor-installer/internal/app"
+)
+
+type MigrationPreview struct {
+	plan *app.MigrationPlan
+}
+
+func NewMigrationPreview(plan *app.MigrationPlan) *MigrationPreview {
+	return &MigrationPreview{plan: plan}
+}
+
+func (p *MigrationPreview) View() string {
+	var s strings.Builder
+
+	s.WriteString(versionHeaderStyle.Render(fmt.Sprintf("Import from %s", p.plan.Source)) + "\n")
+	s.WriteString(styleStepMessage.Render("Source: ") + styleFilePath.Render(p.plan.SourceDir) + "\n\n")
+
+	if p.plan.IsEmpty() {
+		s.WriteString(styleSuccess.Render("Nothing to import, Cursor already matches this configuration.") + "\n")
+		return s.String()
+	}
+
+	writeSection := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		s.WriteString(tableHeaderStyle.Render(fmt.Sprintf("%s (%d)", title, len(items))) + "\n")
+		for _, item := range items {
+			s.WriteString("  " + stylePending.String() + tableValueStyle.Render(item) + "\n")
+		}
+		s.WriteString("\n")
+	}
+
+	writeSection("Settings", p.plan.SettingKeys())
+
+	var keybindings []string
+	for _, entry := range p.plan.Keybindings {
+		if binding, ok := entry.(map[string]interface{}); ok {
+			keybindings = append(keybindings, fmt.Sprintf("%v → %v", binding["key"], binding["command"]))
+		}
+	}
+	writeSection("Keybindings", keybindings)
+	writeSection("Snippets", p.plan.Snippets)
+
+	var extensions []string
+	for _, change := range p.plan.Extensions {
+		extensions = append(extensions, change.ID)
+	}
+	writeSection("Extensions", extensions)
+
+	if len(p.plan.SkippedSettings) > 0 {
+		s.WriteString(styleStepMessage.Render(fmt.Sprintf("%d settings already set in Cursor will be kept (use --overwrite to replace them)", len(p.plan.SkippedSettings))) + "\n")
+	}
+
+	return s.String()
+}
+
+func NewMigrateModel(ctx context.Context, installer *app.Installer, plan *app.MigrationPlan) model {
+	steps := []InstallationStep{
+		{
+			name:    "Import Settings",
+			message: fmt.Sprintf("Merging %d settings...", len(plan.Settings)),
+			run: func(ctx context.Context) error {
+				return installer.ImportSettings(plan)
+			},
+		},
+		{
+			name:    "Import Keybindings",
+			message: fmt.Sprintf("Merging %d keybindings...", len(plan.Keybindings)),
+			run: func(ctx context.Context) error {
+				return installer.ImportKeybindings(plan)
+			},
+		},
+		{
+			name:    "Import Snippets",
+			message: fmt.Sprintf("Copying %d snippet files...", len(plan.Snippets)),
+			run: func(ctx context.Context) error {
+				return installer.ImportSnippets(plan)
+			},
+		},
+	}
+	if len(plan.Extensions) > 0 {
+		steps = append(steps, extensionChangeSteps(installer, plan.Extensions)...)
+	}
+
+	return model{
+		spinner:        newSpinner(),
+		steps:          steps,
+		completedSteps: make([]bool, len(steps)),
+		installer:      installer,
+		title:          "Cursor Migration",
+		successMessage: fmt.Sprintf("✨ Imported %s configuration into Cursor! ✨", plan.Source),
+	}.withContext(ctx)
+}
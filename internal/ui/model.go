@@ -1,7 +1,15 @@
 package ui
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/lutefd/cursor-installer/internal/app"
 )
@@ -9,7 +17,8 @@ import (
 type InstallationStep struct {
 	name    string
 	message string
-	run     func() error
+	run     func(ctx context.Context) error
+	system  bool
 }
 
 type model struct {
@@ -24,24 +33,104 @@ type model struct {
 	installer      *app.Installer
 	steps          []InstallationStep
 	downloadOnly   bool
+	checkInstall   bool
+	title          string
+	successMessage string
+	running        *app.RunningError
+	ctx            context.Context
+	cancel         context.CancelFunc
+	cancelling     bool
+	cleanedUp      []string
+	outputs        []*stepOutput
+	details        viewport.Model
+	showDetails    bool
+	started        time.Time
+	durations      []time.Duration
+	releaseNotes   *app.ReleaseNotesError
+	notes          viewport.Model
+	skipped        string
+}
+
+func newSpinner() spinner.Model {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#4ECDC4"))
+	return s
+}
+
+func userSteps(installer *app.Installer, users []app.TargetUser, build func(*app.Installer) []InstallationStep) []InstallationStep {
+	if len(users) == 0 {
+		return build(installer)
+	}
+
+	var steps []InstallationStep
+	for _, target := range users {
+		scoped := build(installer.ForUser(target))
+		if len(users) > 1 {
+			for idx := range scoped {
+				scoped[idx].name = fmt.Sprintf("%s (%s)", scoped[idx].name, target.Name)
+			}
+		}
+		steps = append(steps, scoped...)
+	}
+	return steps
+}
+
+func hookSteps(installer *app.Installer, point app.HookPoint) []InstallationStep {
+	if !installer.HasHook(point) {
+		return nil
+	}
+
+	return []InstallationStep{{
+		name:    fmt.Sprintf("Run %s Hook", point),
+		message: fmt.Sprintf("Running %s...", installer.HookPath(point)),
+		run: func(ctx context.Context) error {
+			return installer.RunHook(ctx, point)
+		},
+	}}
 }
 
-func checkInstallationWrapper(installer *app.Installer) func() error {
-	return func() error {
-		status := installer.CheckInstallation()
+func checkInstallationWrapper(installer *app.Installer) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		status := installer.CheckInstallation(ctx)
 		return status.Error
 	}
 }
 
-func NewModel(downloadOnly, forceInstall bool, configureSettings bool) model {
-	s := spinner.New()
-	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#4ECDC4"))
+type Options struct {
+	DownloadOnly      bool
+	ForceInstall      bool
+	ConfigureSettings bool
+	ExtensionsFile    string
+	ProfileFile       string
+	Users             []app.TargetUser
+	RunningPolicy     app.RunningPolicy
+	Layout            string
+	Mirror            string
+	HTTP              app.HTTPOptions
+	CacheRetention    int
+	Hooks             map[app.HookPoint]string
+	ChangelogSource   string
+	Changelog         bool
+	Version           string
+}
+
+func NewModel(ctx context.Context, opts Options) model {
+	downloadOnly := opts.DownloadOnly
+	forceInstall := opts.ForceInstall || opts.Version != ""
 
-	installer := app.NewInstaller(downloadOnly, forceInstall, configureSettings)
+	installer := app.NewInstaller(downloadOnly, forceInstall, opts.ConfigureSettings || opts.ProfileFile != "")
+	installer.SetRunningPolicy(opts.RunningPolicy)
+	installer.SetLayout(opts.Layout)
+	installer.SetMirror(opts.Mirror)
+	installer.SetHTTPOptions(opts.HTTP)
+	installer.SetCacheRetention(opts.CacheRetention)
+	installer.SetHooks(opts.Hooks)
+	installer.SetChangelogSource(opts.ChangelogSource)
+	installer.SetTargetVersion(opts.Version)
 
 	var checkMessage string
-	if downloadOnly && !forceInstall && !installer.CheckInstallation().AlreadyUpToDate {
+	if downloadOnly && !forceInstall && !installer.CheckInstallation(ctx).AlreadyUpToDate {
 		checkMessage = "Preparing to download..."
 	} else {
 		checkMessage = "Checking if Cursor is already installed..."
@@ -60,7 +149,9 @@ func NewModel(downloadOnly, forceInstall bool, configureSettings bool) model {
 		},
 	}
 
-	info, err := installer.GetVersionInfo()
+	steps = append(steps, hookSteps(installer, app.HookPreDownload)...)
+
+	info, err := installer.GetVersionInfo(ctx)
 	if err == nil && info.IsInstalled && !forceInstall {
 		var stepName, stepMessage string
 		if downloadOnly {
@@ -74,8 +165,8 @@ func NewModel(downloadOnly, forceInstall bool, configureSettings bool) model {
 		steps = append(steps, InstallationStep{
 			name:    stepName,
 			message: stepMessage,
-			run: func() error {
-				hasUpdate, err := installer.CheckForUpdates()
+			run: func(ctx context.Context) error {
+				hasUpdate, err := installer.CheckForUpdates(ctx)
 				if err != nil {
 					return err
 				}
@@ -85,62 +176,130 @@ func NewModel(downloadOnly, forceInstall bool, configureSettings bool) model {
 				return nil
 			},
 		})
+		if !downloadOnly && opts.Changelog {
+			steps = append(steps, releaseNotesSteps(installer)...)
+		}
 	} else {
+		downloadMessage := "Downloading latest version of Cursor..."
+		if opts.Version != "" {
+			downloadMessage = fmt.Sprintf("Downloading Cursor %s...", opts.Version)
+		}
 		steps = append(steps, InstallationStep{
 			name:    "Download",
-			message: "Downloading latest version of Cursor...",
-			run: func() error {
-				if err := installer.DownloadCursor(); err != nil {
+			message: downloadMessage,
+			run: func(ctx context.Context) error {
+				if err := installer.DownloadCursor(ctx); err != nil {
 					return err
 				}
-				return installer.MakeExecutable()
+				return installer.MakeExecutable(ctx)
 			},
 		})
 	}
 
 	if !downloadOnly {
+		steps = append(steps, hookSteps(installer, app.HookPreInstall)...)
 		steps = append(steps,
+			InstallationStep{
+				name:    "Check Running Instances",
+				message: "Checking whether Cursor is running...",
+				run:     installer.HandleRunningInstances,
+			},
 			InstallationStep{
 				name:    "Install",
 				message: "Installing Cursor...",
 				run:     installer.MoveToOpt,
+				system:  true,
 			},
 			InstallationStep{
 				name:    "Extract Icon",
 				message: "Extracting application icon...",
 				run:     installer.ExtractIcon,
+				system:  true,
 			},
 			InstallationStep{
 				name:    "Create Desktop Entry",
 				message: "Creating desktop entry...",
 				run:     installer.CreateDesktopEntry,
+				system:  true,
 			},
 			InstallationStep{
 				name:    "Create Symlink",
 				message: "Creating command line symlink...",
 				run:     installer.CreateSymlink,
+				system:  true,
 			},
 			InstallationStep{
 				name:    "Update Metadata",
 				message: "Recording installation information...",
 				run:     installer.UpdateMetadata,
+				system:  true,
 			},
 		)
+		steps = append(steps, hookSteps(installer, app.HookPostInstall)...)
+		steps = append(steps, hookSteps(installer, app.HookPostUpdate)...)
 
-		if configureSettings {
-			steps = append(steps, InstallationStep{
-				name:    "Configure Settings",
-				message: "Configuring Cursor settings...",
-				run:     installer.ConfigureCursor,
-			})
-		}
+		steps = append(steps, userSteps(installer, opts.Users, func(installer *app.Installer) []InstallationStep {
+			var configureSteps []InstallationStep
+
+			if opts.ConfigureSettings {
+				configureSteps = append(configureSteps, InstallationStep{
+					name:    "Configure Settings",
+					message: "Configuring Cursor settings...",
+					run: func(ctx context.Context) error {
+						return installer.ConfigureCursor()
+					},
+				})
+			}
+
+			if opts.ProfileFile != "" {
+				configureSteps = append(configureSteps, profileSteps(installer, opts.ProfileFile)...)
+			}
+
+			if opts.ExtensionsFile != "" {
+				configureSteps = append(configureSteps, extensionSteps(installer, opts.ExtensionsFile)...)
+			}
+
+			return configureSteps
+		})...)
 	}
 
 	return model{
-		spinner:        s,
+		spinner:        newSpinner(),
 		steps:          steps,
 		completedSteps: make([]bool, len(steps)),
 		installer:      installer,
 		downloadOnly:   downloadOnly,
+		checkInstall:   !downloadOnly,
+		title:          "Cursor Installer",
+		successMessage: "✨ Cursor installation completed successfully! ✨",
+	}.withContext(ctx)
+}
+
+func (m model) withContext(parent context.Context) model {
+	m.ctx, m.cancel = context.WithCancel(parent)
+	m.outputs = make([]*stepOutput, len(m.steps))
+	for index := range m.outputs {
+		m.outputs[index] = &stepOutput{}
+	}
+	m.details = newDetails()
+	m.started = time.Now()
+	m.durations = make([]time.Duration, len(m.steps))
+	return m
+}
+
+func (m model) completionMessage() string {
+	if m.downloadOnly {
+		pwd, _ := os.Getwd()
+		filePath := filepath.Join(pwd, appImage)
+		return fmt.Sprintf("✨ Cursor downloaded successfully to %s ✨", styleFilePath.Render(filePath))
+	}
+	return m.successMessage
+}
+
+func (m model) plainCompletionMessage() string {
+	if m.downloadOnly {
+		pwd, _ := os.Getwd()
+		return fmt.Sprintf("Cursor downloaded successfully to %s", filepath.Join(pwd, appImage))
 	}
+	return strings.TrimSpace(strings.Trim(m.successMessage, "✨"))
 }
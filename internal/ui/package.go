@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lutefd/cursor-installer/internal/app"
+)
+
+func NewPackageModel(ctx context.Context, installer *app.Installer, format app.PackageFormat, source, outputDir, maintainer string) model {
+	var steps []InstallationStep
+	if source == "" {
+		steps = append(steps, InstallationStep{
+			name:    "Download",
+			message: "Downloading latest version of Cursor...",
+			run:     installer.DownloadCursor,
+		})
+	}
+
+	var pkg *app.PackageSource
+	steps = append(steps,
+		InstallationStep{
+			name:    "Inspect AppImage",
+			message: "Reading version, architecture and icon...",
+			run: func(ctx context.Context) error {
+				var err error
+				pkg, err = installer.InspectAppImage(ctx, source)
+				return err
+			},
+		},
+		InstallationStep{
+			name:    "Build Package",
+			message: fmt.Sprintf("Writing %s package to %s...", format, outputDir),
+			run: func(ctx context.Context) error {
+				if _, err := pkg.Build(format, outputDir, maintainer); err != nil {
+					pkg.Close()
+					return err
+				}
+				return pkg.Close()
+			},
+		},
+	)
+
+	return model{
+		spinner:        newSpinner(),
+		steps:          steps,
+		completedSteps: make([]bool, len(steps)),
+		installer:      installer,
+		title:          "Cursor Packaging",
+		successMessage: fmt.Sprintf("✨ Cursor %s package written to %s! ✨", format, outputDir),
+	}.withContext(ctx)
+}
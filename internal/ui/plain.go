@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/lutefd/cursor-installer/internal/app"
+)
+
+func (m model) RunPlain(out io.Writer, report string) error {
+	progress := out
+	if report == ReportJSON {
+		progress = os.Stderr
+	}
+	logf := func(format string, args ...interface{}) {
+		fmt.Fprintf(progress, "%s %s\n", time.Now().Format(time.RFC3339), fmt.Sprintf(format, args...))
+	}
+
+	m.outputs = nil
+	logf("%s started", m.title)
+
+	status, err := m.runPlainSteps(progress, logf)
+	summary := m.summary(status, err)
+	if report == ReportJSON {
+		if jsonErr := summary.WriteJSON(out); jsonErr != nil {
+			return jsonErr
+		}
+	} else if status == "completed" {
+		summary.WriteText(out)
+	}
+	return err
+}
+
+func (m *model) runPlainSteps(progress io.Writer, logf func(format string, args ...interface{})) (string, error) {
+	for index, step := range m.steps {
+		logf("• %s: %s", step.name, step.message)
+
+		msg := m.runStep(index)
+		var runningErr *app.RunningError
+		if err, ok := msg.(errMsg); ok && errors.As(err, &runningErr) && m.installer.RunningPolicy() == app.RunningAsk {
+			logf("⚠ %v, staging the update for the next launch", runningErr)
+			m.installer.SetRunningPolicy(app.RunningStage)
+			msg = m.runStep(index)
+		}
+		var notesErr *app.ReleaseNotesError
+		if err, ok := msg.(errMsg); ok && errors.As(err, &notesErr) {
+			logf("Release notes for Cursor %s:", notesErr.To)
+			writeReleaseNotes(progress, notesErr)
+			msg = stepCompleteMsg{stepName: step.name, nextStep: index + 1}
+		}
+
+		switch msg := msg.(type) {
+		case errMsg:
+			if m.ctx.Err() != nil {
+				m.cleanedUp = m.installer.Cleanup()
+				logf("✗ %s cancelled", m.title)
+				for _, line := range m.cancelReport() {
+					logf("  %s", line)
+				}
+				return "cancelled", m.ctx.Err()
+			}
+			logf("✗ %s failed: %v", step.name, msg)
+			return "failed", msg
+		case upToDateMsg:
+			logf("✓ Cursor %s is already installed and up to date", msg.version)
+			return "up to date", nil
+		case stepCompleteMsg:
+			m.durations[index] = msg.duration
+		}
+
+		logf("✓ %s", step.name)
+		m.completedSteps[index] = true
+		m.currentStep = index + 1
+	}
+
+	logf("%s", m.plainCompletionMessage())
+	return "completed", nil
+}
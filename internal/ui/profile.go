@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lutefd/cursor-installer/internal/app"
+)
+
+func profileSteps(installer *app.Installer, profileFile string) []InstallationStep {
+	profile, err := app.ReadProfile(profileFile)
+	if err != nil {
+		return []InstallationStep{
+			{
+				name:    "Apply Profile",
+				message: "Reading settings profile...",
+				run: func(ctx context.Context) error {
+					return err
+				},
+			},
+		}
+	}
+
+	steps := []InstallationStep{
+		{
+			name:    "Apply Profile",
+			message: fmt.Sprintf("Applying %d settings and %d keybindings...", len(profile.Settings), len(profile.Keybindings)),
+			run: func(ctx context.Context) error {
+				return installer.ApplyProfile(profile)
+			},
+		},
+	}
+
+	if len(profile.Extensions) > 0 {
+		changes, err := installer.PlanExtensions(profile.Extensions)
+		if err != nil {
+			return append(steps, InstallationStep{
+				name:    "Extensions",
+				message: "Comparing installed extensions...",
+				run: func(ctx context.Context) error {
+					return err
+				},
+			})
+		}
+		steps = append(steps, extensionChangeSteps(installer, changes)...)
+	}
+
+	return steps
+}
+
+func NewProfileModel(ctx context.Context, profileFile string, users []app.TargetUser) model {
+	installer := app.NewInstaller(false, false, true)
+	steps := userSteps(installer, users, func(installer *app.Installer) []InstallationStep {
+		return profileSteps(installer, profileFile)
+	})
+
+	return model{
+		spinner:        newSpinner(),
+		steps:          steps,
+		completedSteps: make([]bool, len(steps)),
+		installer:      installer,
+		title:          "Cursor Profile",
+		successMessage: "✨ Cursor profile applied successfully! ✨",
+	}.withContext(ctx)
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lutefd/cursor-installer/internal/app"
+)
+
+func RepairPlanView(actions []app.RepairAction) string {
+	var s strings.Builder
+
+	s.WriteString(versionHeaderStyle.Render("Cursor Repair") + "\n\n")
+
+	if len(actions) == 0 {
+		s.WriteString(styleSuccess.Render("✨ Nothing to repair, the installation matches its manifest! ✨") + "\n")
+		return s.String()
+	}
+
+	for _, action := range actions {
+		s.WriteString(fmt.Sprintf("  %s%s %s\n", stylePending.String(), tableValueStyle.Render(action.Name), styleStepMessage.Render("("+action.Reason+")")))
+	}
+
+	return s.String()
+}
+
+func NewRepairModel(ctx context.Context, installer *app.Installer, actions []app.RepairAction) model {
+	steps := []InstallationStep{
+		{
+			name:    "Check Permissions",
+			message: "Checking sudo access...",
+			run:     installer.CheckSudoAccess,
+		},
+	}
+	for _, action := range actions {
+		steps = append(steps, InstallationStep{
+			name:    action.Name,
+			message: action.Reason,
+			run:     action.Run,
+			system:  true,
+		})
+	}
+
+	return model{
+		spinner:        newSpinner(),
+		steps:          steps,
+		completedSteps: make([]bool, len(steps)),
+		installer:      installer,
+		title:          "Cursor Repair",
+		successMessage: "✨ Cursor installation repaired successfully! ✨",
+	}.withContext(ctx)
+}
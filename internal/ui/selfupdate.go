@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lutefd/cursor-installer/internal/app"
+)
+
+func NewSelfUpdateModel(ctx context.Context, installer *app.Installer, release *app.InstallerRelease) model {
+	var download *app.InstallerDownload
+	steps := []InstallationStep{
+		{
+			name:    "Download",
+			message: fmt.Sprintf("Downloading %s...", release.Archive),
+			run: func(ctx context.Context) error {
+				var err error
+				download, err = installer.DownloadInstallerRelease(ctx, release)
+				return err
+			},
+		},
+		{
+			name:    "Verify Checksum",
+			message: "Checking the archive against the published checksums...",
+			run: func(ctx context.Context) error {
+				if err := installer.VerifyInstallerRelease(ctx, release, download); err != nil {
+					installer.DiscardInstallerDownload(download)
+					return err
+				}
+				return nil
+			},
+		},
+		{
+			name:    "Replace Executable",
+			message: "Swapping in the new cursor-installer binary...",
+			run: func(ctx context.Context) error {
+				_, err := installer.ReplaceExecutable(ctx, download)
+				return err
+			},
+		},
+	}
+
+	return model{
+		spinner:        newSpinner(),
+		steps:          steps,
+		completedSteps: make([]bool, len(steps)),
+		installer:      installer,
+		title:          "cursor-installer Self-Update",
+		successMessage: fmt.Sprintf("✨ cursor-installer updated to %s! ✨", release.Version),
+	}.withContext(ctx)
+}
+
+func InstallerReleaseView(release *app.InstallerRelease) string {
+	if !release.Newer() {
+		return styleSuccess.Render(fmt.Sprintf("✨ cursor-installer %s is up to date ✨", app.InstallerVersion))
+	}
+	return styleStepMessage.Render(fmt.Sprintf("cursor-installer %s is available (installed: %s), run `cursor-installer self-update` to install it", release.Version, app.InstallerVersion))
+}
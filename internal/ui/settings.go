@@ -0,0 +1,312 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lutefd/cursor-installer/internal/app"
+)
+
+type editorMode int
+
+const (
+	modeBrowse editorMode = iota
+	modeEditText
+	modeEditList
+	modeAddListItem
+)
+
+type settingsEditor struct {
+	installer *app.Installer
+	settings  []app.CursorSetting
+	dirty     map[string]bool
+	cursor    int
+	listIndex int
+	mode      editorMode
+	input     textinput.Model
+	err       error
+	saved     bool
+	quitting  bool
+
+	confirmQuit bool
+}
+
+func NewSettingsEditor(target app.TargetUser) settingsEditor {
+	installer := app.NewInstaller(false, false, true).ForUser(target)
+	settings, err := installer.CursorSettings()
+
+	input := textinput.New()
+	input.Prompt = "› "
+	input.PromptStyle = styleCurrentStep
+	input.CharLimit = 256
+
+	return settingsEditor{
+		installer: installer,
+		settings:  settings,
+		dirty:     make(map[string]bool),
+		input:     input,
+		err:       err,
+	}
+}
+
+func (e settingsEditor) Init() tea.Cmd {
+	if e.err != nil {
+		return tea.Sequence(
+			tea.Println(styleError.Render(fmt.Sprintf("Error: %v", e.err))),
+			tea.Quit,
+		)
+	}
+	return nil
+}
+
+func (e settingsEditor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return e, nil
+	}
+
+	if keyMsg.Type == tea.KeyCtrlC {
+		e.quitting = true
+		return e, tea.Quit
+	}
+
+	switch e.mode {
+	case modeEditText, modeAddListItem:
+		return e.updateInput(keyMsg)
+	case modeEditList:
+		return e.updateList(keyMsg)
+	default:
+		return e.updateBrowse(keyMsg)
+	}
+}
+
+func (e settingsEditor) updateBrowse(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	confirmQuit := e.confirmQuit
+	e.confirmQuit = false
+
+	switch msg.String() {
+	case "up", "k":
+		if e.cursor > 0 {
+			e.cursor--
+		}
+	case "down", "j":
+		if e.cursor < len(e.settings)-1 {
+			e.cursor++
+		}
+	case "enter", " ":
+		setting := &e.settings[e.cursor]
+		switch setting.Kind {
+		case app.SettingBool:
+			setting.Value = !setting.Value.(bool)
+			e.markDirty(setting)
+		case app.SettingString:
+			e.mode = modeEditText
+			e.input.SetValue(setting.Value.(string))
+			e.input.CursorEnd()
+			return e, e.input.Focus()
+		case app.SettingList:
+			e.mode = modeEditList
+			e.listIndex = 0
+		}
+	case "s", "ctrl+s":
+		return e.save()
+	case "q", "esc":
+		if len(e.dirty) > 0 && !confirmQuit {
+			e.confirmQuit = true
+			return e, nil
+		}
+		e.quitting = true
+		return e, tea.Quit
+	}
+	return e, nil
+}
+
+func (e settingsEditor) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	setting := &e.settings[e.cursor]
+	items := setting.Value.([]string)
+
+	switch msg.String() {
+	case "up", "k":
+		if e.listIndex > 0 {
+			e.listIndex--
+		}
+	case "down", "j":
+		if e.listIndex < len(items)-1 {
+			e.listIndex++
+		}
+	case "a":
+		e.mode = modeAddListItem
+		e.input.SetValue("")
+		return e, e.input.Focus()
+	case "d", "x", "backspace":
+		if len(items) > 0 {
+			updated := append(append([]string{}, items[:e.listIndex]...), items[e.listIndex+1:]...)
+			setting.Value = updated
+			e.markDirty(setting)
+			if e.listIndex >= len(updated) && e.listIndex > 0 {
+				e.listIndex--
+			}
+		}
+	case "esc", "enter", "q":
+		e.mode = modeBrowse
+	}
+	return e, nil
+}
+
+func (e settingsEditor) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	setting := &e.settings[e.cursor]
+
+	switch msg.Type {
+	case tea.KeyEnter:
+		value := strings.TrimSpace(e.input.Value())
+		if e.mode == modeAddListItem {
+			if value != "" {
+				setting.Value = append(setting.Value.([]string), value)
+				e.listIndex = len(setting.Value.([]string)) - 1
+				e.markDirty(setting)
+			}
+			e.mode = modeEditList
+		} else {
+			setting.Value = value
+			e.markDirty(setting)
+			e.mode = modeBrowse
+		}
+		e.input.Blur()
+		return e, nil
+	case tea.KeyEsc:
+		if e.mode == modeAddListItem {
+			e.mode = modeEditList
+		} else {
+			e.mode = modeBrowse
+		}
+		e.input.Blur()
+		return e, nil
+	}
+
+	var cmd tea.Cmd
+	e.input, cmd = e.input.Update(msg)
+	return e, cmd
+}
+
+func (e *settingsEditor) markDirty(setting *app.CursorSetting) {
+	setting.IsSet = true
+	e.dirty[setting.Key] = true
+}
+
+func (e settingsEditor) save() (tea.Model, tea.Cmd) {
+	changes := make(map[string]interface{})
+	for _, setting := range e.settings {
+		if e.dirty[setting.Key] {
+			changes[setting.Key] = setting.Value
+		}
+	}
+
+	if len(changes) == 0 {
+		e.quitting = true
+		return e, tea.Sequence(
+			tea.Println(styleStepMessage.Render("  No changes to save")),
+			tea.Quit,
+		)
+	}
+
+	if err := e.installer.MergeSettings(changes); err != nil {
+		e.err = err
+		return e, tea.Sequence(
+			tea.Println(styleError.Render(fmt.Sprintf("Error: %v", err))),
+			tea.Quit,
+		)
+	}
+
+	e.saved = true
+	return e, tea.Sequence(
+		tea.Println(styleSuccess.Render(fmt.Sprintf("✨ Saved %d Cursor settings! ✨", len(changes)))),
+		tea.Quit,
+	)
+}
+
+func (e settingsEditor) View() string {
+	if e.err != nil || e.saved || e.quitting {
+		return ""
+	}
+
+	var s strings.Builder
+
+	s.WriteString(styleTitle.Render("Cursor Settings") + "\n\n")
+
+	for n, setting := range e.settings {
+		prefix := "  "
+		keyStyle := stylePendingStep
+		if n == e.cursor {
+			prefix = styleCurrentStep.Render("› ")
+			keyStyle = styleCurrentStep
+		}
+
+		marker := " "
+		if e.dirty[setting.Key] {
+			marker = styleModified.String()
+		}
+
+		s.WriteString(prefix + marker + keyStyle.Render(setting.Key) + "  " + formatSettingValue(setting) + "\n")
+
+		if n == e.cursor {
+			s.WriteString("     " + styleStepMessage.Render(setting.Description) + "\n")
+			switch e.mode {
+			case modeEditText:
+				s.WriteString("     " + e.input.View() + "\n")
+			case modeEditList, modeAddListItem:
+				for idx, item := range setting.Value.([]string) {
+					itemPrefix := "       "
+					if idx == e.listIndex && e.mode == modeEditList {
+						itemPrefix = "     " + styleCurrentStep.Render("› ")
+					}
+					s.WriteString(itemPrefix + tableValueStyle.Render(item) + "\n")
+				}
+				if e.mode == modeAddListItem {
+					s.WriteString("     " + e.input.View() + "\n")
+				}
+			}
+		}
+	}
+
+	if e.confirmQuit {
+		s.WriteString("\n" + styleWarning.Render("Unsaved changes, press s to save or q again to discard") + "\n")
+	} else {
+		s.WriteString("\n" + styleHelp.Render(e.helpText()) + "\n")
+	}
+
+	return s.String()
+}
+
+func (e settingsEditor) helpText() string {
+	switch e.mode {
+	case modeEditText, modeAddListItem:
+		return "enter confirm • esc cancel"
+	case modeEditList:
+		return "↑/↓ move • a add • d delete • esc done"
+	default:
+		return "↑/↓ move • enter/space edit • s save • q quit"
+	}
+}
+
+func formatSettingValue(setting app.CursorSetting) string {
+	if !setting.IsSet {
+		return stylePendingStep.Render("(not set)")
+	}
+
+	switch value := setting.Value.(type) {
+	case bool:
+		if value {
+			return styleCompleted.String() + tableValueStyle.Render("on")
+		}
+		return tableValueStyle.Render("off")
+	case []string:
+		if len(value) == 0 {
+			return tableValueStyle.Render("[]")
+		}
+		return tableValueStyle.Render(strings.Join(value, ", "))
+	default:
+		return tableValueStyle.Render(fmt.Sprintf("%q", value))
+	}
+}
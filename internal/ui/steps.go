@@ -1,35 +1,100 @@
 package ui
 
-import tea "github.com/charmbracelet/bubbletea"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lutefd/cursor-installer/internal/app"
+)
 
 func (m model) runNextStep() tea.Cmd {
 	return func() tea.Msg {
 		if m.currentStep >= len(m.steps) {
 			return doneMsg{}
 		}
+		return m.runStep(m.currentStep)
+	}
+}
 
-		step := m.steps[m.currentStep]
+func (m model) runStep(index int) tea.Msg {
+	step := m.steps[index]
+	ctx := m.ctx
+	if m.outputs != nil {
+		ctx = app.WithOutput(ctx, m.outputs[index])
+	}
 
-		if m.currentStep == 0 && !m.downloadOnly {
-			status := m.installer.CheckInstallation()
-			if status.Error != nil {
-				return errMsg(status.Error)
-			}
-			if status.AlreadyUpToDate {
-				return upToDateMsg{version: status.CurrentVersion}
-			}
+	if index == 0 && m.checkInstall {
+		status := m.installer.CheckInstallation(ctx)
+		if status.Error != nil {
+			app.Logger().Error("installation check failed", "error", status.Error.Error())
+			return errMsg(status.Error)
+		}
+		if status.AlreadyUpToDate {
+			return upToDateMsg{version: status.CurrentVersion}
 		}
+	}
 
-		if err := step.run(); err != nil {
-			if upToDateErr, ok := err.(*upToDateError); ok {
-				return upToDateMsg{version: upToDateErr.version}
-			}
+	started := time.Now()
+	app.Logger().Info("step started", "step", step.name, "message", step.message)
+	if err := step.run(ctx); err != nil {
+		if upToDateErr, ok := err.(*upToDateError); ok {
+			app.Logger().Info("step finished", "step", step.name, "duration", time.Since(started), "result", "up to date")
+			return upToDateMsg{version: upToDateErr.version}
+		}
+		var notesErr *app.ReleaseNotesError
+		if errors.As(err, &notesErr) {
+			app.Logger().Info("step finished", "step", step.name, "duration", time.Since(started), "result", fmt.Sprintf("%d release notes", len(notesErr.Releases)))
 			return errMsg(err)
 		}
+		app.Logger().Error("step failed", "step", step.name, "duration", time.Since(started), "error", err.Error())
+		return errMsg(err)
+	}
+	duration := time.Since(started)
+	app.Logger().Info("step finished", "step", step.name, "duration", duration)
+
+	return stepCompleteMsg{
+		stepName: step.name,
+		nextStep: index + 1,
+		duration: duration,
+	}
+}
+
+func (m model) waitForCancel() tea.Cmd {
+	return func() tea.Msg {
+		<-m.ctx.Done()
+		return cancelMsg{}
+	}
+}
 
-		return stepCompleteMsg{
-			stepName: step.name,
-			nextStep: m.currentStep + 1,
+func (m model) cancelReport() []string {
+	var completed []string
+	var changed bool
+	for index, step := range m.steps {
+		if m.completedSteps[index] {
+			completed = append(completed, step.name)
+			changed = changed || step.system
 		}
 	}
+
+	var lines []string
+	if m.currentStep < len(m.steps) && !m.completedSteps[m.currentStep] {
+		step := m.steps[m.currentStep]
+		lines = append(lines, fmt.Sprintf("Stopped during %q", step.name))
+		changed = changed || step.system
+	}
+	if len(completed) > 0 {
+		lines = append(lines, "Completed: "+strings.Join(completed, ", "))
+	}
+	for _, path := range m.cleanedUp {
+		lines = append(lines, "Removed "+path)
+	}
+	if changed {
+		lines = append(lines, "The installation may be incomplete, run `cursor-installer repair` or `cursor-installer --force` to restore it")
+	} else {
+		lines = append(lines, "The installed Cursor was not modified")
+	}
+	return lines
 }
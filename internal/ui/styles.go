@@ -28,6 +28,11 @@ var (
 			Bold(true).
 			PaddingLeft(2)
 
+	styleWarning = lipgloss.NewStyle().
+			Foreground(warningColor).
+			Bold(true).
+			PaddingLeft(2)
+
 	styleProgress = lipgloss.NewStyle().
 			Foreground(secondaryColor).
 			PaddingLeft(2)
@@ -62,4 +67,21 @@ var (
 	styleStepMessage = lipgloss.NewStyle().
 				Foreground(secondaryColor).
 				Italic(true)
+
+	styleModified = lipgloss.NewStyle().
+			Foreground(warningColor).
+			SetString("*")
+
+	styleHelp = lipgloss.NewStyle().
+			Foreground(textColor).
+			Faint(true).
+			PaddingLeft(2)
+
+	styleDetails = lipgloss.NewStyle().
+			Foreground(textColor).
+			Faint(true).
+			BorderStyle(lipgloss.RoundedBorder()).
+			BorderForeground(secondaryColor).
+			MarginLeft(2).
+			Padding(0, 1)
 )
@@ -0,0 +1,164 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lutefd/cursor-installer/internal/app"
+)
+
+const (
+	ReportText = "text"
+	ReportJSON = "json"
+)
+
+var (
+	summaryStyle        = lipgloss.NewStyle().PaddingLeft(2)
+	summaryWarningStyle = lipgloss.NewStyle().Foreground(warningColor).Bold(true)
+)
+
+type StepTiming struct {
+	Name     string        `json:"name"`
+	Status   string        `json:"status"`
+	Duration time.Duration `json:"duration"`
+}
+
+type Summary struct {
+	Title    string        `json:"title"`
+	Status   string        `json:"status"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+	Steps    []StepTiming  `json:"steps"`
+	app.Report
+}
+
+func (m model) summary(status string, err error) Summary {
+	summary := Summary{
+		Title:    m.title,
+		Status:   status,
+		Duration: time.Since(m.started).Round(time.Millisecond),
+		Report:   m.installer.Report(),
+	}
+	if err != nil {
+		summary.Error = err.Error()
+	}
+
+	for index, step := range m.steps {
+		timing := StepTiming{Name: step.name, Status: "pending"}
+		switch {
+		case m.completedSteps[index]:
+			timing.Status = "completed"
+			timing.Duration = m.durations[index].Round(time.Millisecond)
+		case index == m.currentStep && status == "cancelled":
+			timing.Status = "cancelled"
+		case index == m.currentStep && err != nil:
+			timing.Status = "failed"
+		}
+		summary.Steps = append(summary.Steps, timing)
+	}
+	return summary
+}
+
+func (s Summary) versionChange() string {
+	switch {
+	case s.Version == "":
+		return ""
+	case s.PreviousVersion == "" || s.PreviousVersion == s.Version:
+		return s.Version
+	default:
+		return s.PreviousVersion + " → " + s.Version
+	}
+}
+
+func (s Summary) downloadLine() string {
+	switch {
+	case s.FromCache:
+		return "from cache"
+	case s.Downloaded > 0:
+		return formatSize(s.Downloaded)
+	default:
+		return ""
+	}
+}
+
+func (s Summary) View() string {
+	var b strings.Builder
+
+	if version := s.versionChange(); version != "" {
+		b.WriteString(styleStepMessage.Render("Version: ") + tableValueStyle.Render(version) + "\n")
+	}
+	if download := s.downloadLine(); download != "" {
+		b.WriteString(styleStepMessage.Render("Downloaded: ") + tableValueStyle.Render(download) + "\n")
+	}
+	b.WriteString(styleStepMessage.Render("Total time: ") + tableValueStyle.Render(s.Duration.String()) + "\n\n")
+
+	header := []string{
+		tableHeaderStyle.Render("Step"),
+		tableHeaderStyle.Render("Duration"),
+	}
+	var data [][]string
+	for _, step := range s.Steps {
+		duration := step.Status
+		if step.Status == "completed" {
+			duration = step.Duration.String()
+		}
+		data = append(data, []string{tableRowStyle.Render(step.Name), tableValueStyle.Render(duration)})
+	}
+	b.WriteString(renderTable(header, data) + "\n")
+
+	if len(s.Files) > 0 || len(s.Settings) > 0 {
+		b.WriteString("\n" + tableHeaderStyle.Render("Changes") + "\n")
+		for _, file := range s.Files {
+			b.WriteString(styleStepMessage.Render(fmt.Sprintf("  %-8s ", file.Action)) + styleFilePath.Render(file.Path) + "\n")
+		}
+		for _, settings := range s.Settings {
+			b.WriteString(styleStepMessage.Render("  settings ") + styleFilePath.Render(settings.Path) + styleStepMessage.Render(": "+strings.Join(settings.Keys, ", ")) + "\n")
+		}
+	}
+
+	if len(s.Warnings) > 0 {
+		b.WriteString("\n")
+		for _, warning := range s.Warnings {
+			b.WriteString(summaryWarningStyle.Render("⚠ "+warning) + "\n")
+		}
+	}
+
+	return summaryStyle.Render(strings.TrimRight(b.String(), "\n"))
+}
+
+func (s Summary) WriteText(out io.Writer) {
+	fmt.Fprintf(out, "Summary: %s %s in %s\n", s.Title, s.Status, s.Duration)
+	if version := s.versionChange(); version != "" {
+		fmt.Fprintf(out, "  Version: %s\n", version)
+	}
+	if download := s.downloadLine(); download != "" {
+		fmt.Fprintf(out, "  Downloaded: %s\n", download)
+	}
+	for _, step := range s.Steps {
+		if step.Status == "completed" {
+			fmt.Fprintf(out, "  Step %s: %s\n", step.Name, step.Duration)
+		} else {
+			fmt.Fprintf(out, "  Step %s: %s\n", step.Name, step.Status)
+		}
+	}
+	for _, file := range s.Files {
+		fmt.Fprintf(out, "  File %s: %s\n", file.Path, file.Action)
+	}
+	for _, settings := range s.Settings {
+		fmt.Fprintf(out, "  Changed settings in %s: %s\n", settings.Path, strings.Join(settings.Keys, ", "))
+	}
+	for _, warning := range s.Warnings {
+		fmt.Fprintf(out, "  Warning: %s\n", warning)
+	}
+}
+
+func (s Summary) WriteJSON(out io.Writer) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
@@ -1,6 +1,10 @@
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 var (
 	tableHeaderStyle = lipgloss.NewStyle().
@@ -26,3 +30,57 @@ var (
 				Padding(0, 1).
 				Align(lipgloss.Center)
 )
+
+func renderTable(header []string, data [][]string) string {
+	var s strings.Builder
+
+	widths := make([]int, len(header))
+	for i, cell := range header {
+		widths[i] = lipgloss.Width(cell)
+	}
+
+	for _, row := range data {
+		for i, cell := range row {
+			if w := lipgloss.Width(cell); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	createBorder := func(left, mid, right, horizontal string) string {
+		var border strings.Builder
+		border.WriteString(tableBorderStyle.Render(left))
+		for i, width := range widths {
+			border.WriteString(strings.Repeat(tableBorderStyle.Render(horizontal), width+2))
+			if i < len(widths)-1 {
+				border.WriteString(tableBorderStyle.Render(mid))
+			}
+		}
+		border.WriteString(tableBorderStyle.Render(right))
+		return border.String()
+	}
+
+	s.WriteString(createBorder("┌", "┬", "┐", "─") + "\n")
+
+	s.WriteString(tableBorderStyle.Render("│"))
+	for i, cell := range header {
+		padding := widths[i] - lipgloss.Width(cell)
+		s.WriteString(" " + cell + strings.Repeat(" ", padding) + " " + tableBorderStyle.Render("│"))
+	}
+	s.WriteString("\n")
+
+	s.WriteString(createBorder("├", "┼", "┤", "─") + "\n")
+
+	for _, row := range data {
+		s.WriteString(tableBorderStyle.Render("│"))
+		for i, cell := range row {
+			padding := widths[i] - lipgloss.Width(cell)
+			s.WriteString(" " + cell + strings.Repeat(" ", padding) + " " + tableBorderStyle.Render("│"))
+		}
+		s.WriteString("\n")
+	}
+
+	s.WriteString(createBorder("└", "┴", "┘", "─") + "\n")
+
+	return s.String()
+}
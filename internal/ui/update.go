@@ -1,61 +1,103 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lutefd/cursor-installer/internal/app"
 )
 
 func (m model) Init() tea.Cmd {
 	return tea.Batch(
 		m.spinner.Tick,
 		m.runNextStep(),
+		m.waitForCancel(),
 	)
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if msg.String() == "v" {
+			m.showDetails = !m.showDetails
+			if m.showDetails {
+				m.details.GotoBottom()
+			}
+			return m.refreshDetails(), nil
+		}
+		if m.running != nil && msg.Type != tea.KeyCtrlC {
+			return m.handleRunningChoice(msg)
+		}
+		if m.releaseNotes != nil && msg.Type != tea.KeyCtrlC {
+			return m.handleReleaseNotesChoice(msg)
+		}
 		if msg.Type == tea.KeyCtrlC {
-			m.cancelled = true
-			return m, tea.Sequence(
-				tea.Println(styleError.Render("Installation cancelled by user")),
-				tea.Quit,
-			)
+			m.cancel()
+			return m, nil
+		}
+		if m.showDetails {
+			var cmd tea.Cmd
+			m.details, cmd = m.details.Update(msg)
+			return m, cmd
+		}
+
+	case tea.WindowSizeMsg:
+		m.details.Width = max(min(msg.Width-6, detailsWidth), 20)
+		if m.releaseNotes != nil {
+			m.notes.Width = m.details.Width
+			m.notes.SetContent(renderReleaseNotes(m.releaseNotes.Releases, m.notes.Width))
 		}
+		return m.refreshDetails(), nil
+
+	case cancelMsg:
+		if m.completed || m.upToDate || m.err != nil || m.cancelled {
+			return m, nil
+		}
+		m.cancelling = true
+		if m.running != nil || m.releaseNotes != nil {
+			return m.finishCancel()
+		}
+		return m, nil
 
 	case spinner.TickMsg:
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
-		return m, cmd
+		return m.refreshDetails(), cmd
 
 	case stepCompleteMsg:
 		m.completedSteps[m.currentStep] = true
+		m.durations[m.currentStep] = msg.duration
+		if m.cancelling {
+			return m.finishCancel()
+		}
 		m.currentStep = msg.nextStep
 		if m.currentStep < len(m.steps) {
 			return m, m.runNextStep()
 		}
-		m.completed = true
-		successMsg := "✨ Cursor installation completed successfully! ✨"
-		if m.downloadOnly {
-			pwd, _ := os.Getwd()
-			filePath := filepath.Join(pwd, appImage)
-			successMsg = fmt.Sprintf("✨ Cursor downloaded successfully to %s ✨", styleFilePath.Render(filePath))
-		}
-		return m, tea.Sequence(
-			tea.Println(styleSuccess.Render(successMsg)),
-			tea.Quit,
-		)
+		return m.finishCompleted()
 
 	case errMsg:
+		if m.cancelling || m.ctx.Err() != nil {
+			return m.finishCancel()
+		}
+		var runningErr *app.RunningError
+		if errors.As(msg, &runningErr) {
+			m.running = runningErr
+			return m, nil
+		}
+		var notesErr *app.ReleaseNotesError
+		if errors.As(msg, &notesErr) {
+			return m.showReleaseNotes(notesErr), nil
+		}
 		m.err = msg
-		return m, tea.Sequence(
-			tea.Println(styleError.Render(fmt.Sprintf("Error: %v", m.err))),
-			tea.Quit,
-		)
+		m.showDetails = true
+		cmds := []tea.Cmd{tea.Println(styleError.Render(fmt.Sprintf("Error: %v", m.err)))}
+		if output := m.failureOutput(); output != "" {
+			cmds = append(cmds, tea.Println(styleStepMessage.Render("  Output of "+m.steps[m.detailsStep()].name+":")), tea.Println(output))
+		}
+		return m, tea.Sequence(append(cmds, tea.Quit)...)
 
 	case upToDateMsg:
 		m.upToDate = true
@@ -66,18 +108,54 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		)
 
 	case doneMsg:
-		m.completed = true
-		successMsg := "✨ Cursor installation completed successfully! ✨"
-		if m.downloadOnly {
-			pwd, _ := os.Getwd()
-			filePath := filepath.Join(pwd, appImage)
-			successMsg = fmt.Sprintf("✨ Cursor downloaded successfully to %s ✨", styleFilePath.Render(filePath))
-		}
-		return m, tea.Sequence(
-			tea.Println(styleSuccess.Render(successMsg)),
-			tea.Quit,
-		)
+		return m.finishCompleted()
 	}
 
 	return m, nil
 }
+
+func (m model) handleRunningChoice(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var policy app.RunningPolicy
+	var message string
+
+	switch msg.String() {
+	case "w":
+		policy, message = app.RunningWait, "Waiting for Cursor to close..."
+	case "c":
+		policy, message = app.RunningClose, fmt.Sprintf("Asking Cursor to close (pid %s)...", m.running.PIDList())
+	case "s":
+		policy, message = app.RunningStage, "Staging update for the next launch..."
+	case "q", "esc":
+		m.cancel()
+		return m, nil
+	default:
+		return m, nil
+	}
+
+	m.installer.SetRunningPolicy(policy)
+	m.steps[m.currentStep].message = message
+	m.running = nil
+	return m, m.runNextStep()
+}
+
+func (m model) finishCompleted() (tea.Model, tea.Cmd) {
+	m.completed = true
+	return m, tea.Sequence(
+		tea.Println(styleSuccess.Render(m.completionMessage())),
+		tea.Println(m.summary("completed", nil).View()),
+		tea.Quit,
+	)
+}
+
+func (m model) finishCancel() (tea.Model, tea.Cmd) {
+	m.cancelled = true
+	m.running = nil
+	m.releaseNotes = nil
+	m.cleanedUp = m.installer.Cleanup()
+
+	cmds := []tea.Cmd{tea.Println(styleError.Render("Installation cancelled by user"))}
+	for _, line := range m.cancelReport() {
+		cmds = append(cmds, tea.Println(styleStepMessage.Render("  "+line)))
+	}
+	return m, tea.Sequence(append(cmds, tea.Quit)...)
+}
@@ -3,8 +3,8 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
 
-	"github.com/charmbracelet/lipgloss"
 	"github.com/lutefd/cursor-installer/internal/app"
 )
 
@@ -41,60 +41,58 @@ func (v *VersionDisplay) View() string {
 			tableValueStyle.Render(v.info.CursorVersion),
 			tableRowStyle.Render(getInstallStatus(v.info.IsInstalled)),
 		},
+		{
+			tableRowStyle.Render("Latest"),
+			tableValueStyle.Render(latestVersion(v.info)),
+			tableRowStyle.Render(latestStatus(v.info)),
+		},
 		{
 			tableRowStyle.Render("Installer"),
 			tableValueStyle.Render(v.info.InstallerVersion),
-			tableRowStyle.Render("installed"),
+			tableRowStyle.Render(installerStatus(v.info.InstallerUpdate)),
 		},
 	}
 
-	widths := make([]int, 3)
-	for i, cell := range header {
-		widths[i] = lipgloss.Width(cell)
-	}
+	s.WriteString(renderTable(header, data))
+	s.WriteString("\n")
 
-	for _, row := range data {
-		for i, cell := range row {
-			if w := lipgloss.Width(cell); w > widths[i] {
-				widths[i] = w
-			}
-		}
+	header = []string{
+		tableHeaderStyle.Render("Detail"),
+		tableHeaderStyle.Render("Value"),
 	}
 
-	createBorder := func(left, mid, right, horizontal string) string {
-		var border strings.Builder
-		border.WriteString(tableBorderStyle.Render(left))
-		for i, width := range widths {
-			border.WriteString(strings.Repeat(tableBorderStyle.Render(horizontal), width+2))
-			if i < len(widths)-1 {
-				border.WriteString(tableBorderStyle.Render(mid))
-			}
-		}
-		border.WriteString(tableBorderStyle.Render(right))
-		return border.String()
+	details := [][2]string{
+		{"Installer commit", orUnknown(shortCommit(v.info.InstallerCommit))},
+		{"Installer built", orUnknown(v.info.InstallerBuildDate)},
+		{"Install scope", v.info.Scope},
+		{"Install path", v.info.InstallPath},
 	}
-
-	s.WriteString(createBorder("┌", "┬", "┐", "─") + "\n")
-
-	s.WriteString(tableBorderStyle.Render("│"))
-	for i, cell := range header {
-		padding := widths[i] - lipgloss.Width(cell)
-		s.WriteString(" " + cell + strings.Repeat(" ", padding) + " " + tableBorderStyle.Render("│"))
+	if v.info.IsInstalled {
+		details = append(details,
+			[2]string{"Installed", formatDate(v.info.InstallDate)},
+			[2]string{"Last updated", formatDate(v.info.LastUpdate)},
+		)
+	}
+	details = append(details, [2]string{"Channel", orUnknown(v.info.Channel)})
+	if v.info.IsInstalled {
+		details = append(details, [2]string{"Checksum", orUnknown(v.info.Checksum)})
 	}
-	s.WriteString("\n")
-
-	s.WriteString(createBorder("├", "┼", "┤", "─") + "\n")
 
-	for _, row := range data {
-		s.WriteString(tableBorderStyle.Render("│"))
-		for i, cell := range row {
-			padding := widths[i] - lipgloss.Width(cell)
-			s.WriteString(" " + cell + strings.Repeat(" ", padding) + " " + tableBorderStyle.Render("│"))
-		}
-		s.WriteString("\n")
+	data = nil
+	for _, detail := range details {
+		data = append(data, []string{
+			tableRowStyle.Render(detail[0]),
+			tableValueStyle.Render(detail[1]),
+		})
+	}
+	for _, result := range v.info.Integration {
+		data = append(data, []string{
+			tableRowStyle.Render(result.Name),
+			checkStatusStyle(result.Status).Render(integrationStatus(result)),
+		})
 	}
 
-	s.WriteString(createBorder("└", "┴", "┘", "─") + "\n")
+	s.WriteString(renderTable(header, data))
 
 	return s.String()
 }
@@ -105,3 +103,64 @@ func getInstallStatus(installed bool) string {
 	}
 	return "not installed"
 }
+
+func installerStatus(update string) string {
+	if update != "" {
+		return fmt.Sprintf("installer update available (%s)", update)
+	}
+	return "installed"
+}
+
+func latestStatus(info *app.VersionInfo) string {
+	switch {
+	case !info.LatestChecked:
+		return "not checked, use --check"
+	case info.LatestVersion == "":
+		return "could not be checked"
+	case !info.IsInstalled:
+		return "available"
+	case info.LatestVersion == info.CursorVersion:
+		return "up to date"
+	default:
+		return "update available"
+	}
+}
+
+func latestVersion(info *app.VersionInfo) string {
+	if !info.LatestChecked {
+		return "-"
+	}
+	return orUnknown(info.LatestVersion)
+}
+
+func integrationStatus(result app.DiagnosticResult) string {
+	if result.Status == app.CheckPass {
+		return "healthy"
+	}
+	return fmt.Sprintf("%s: %s", result.Status, result.Detail)
+}
+
+func shortCommit(commit string) string {
+	hash, dirty := strings.CutSuffix(commit, "-dirty")
+	if len(hash) > 12 {
+		hash = hash[:12]
+	}
+	if dirty {
+		return hash + "-dirty"
+	}
+	return hash
+}
+
+func formatDate(date time.Time) string {
+	if date.IsZero() {
+		return "unknown"
+	}
+	return date.Local().Format("2006-01-02 15:04")
+}
+
+func orUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lutefd/cursor-installer/internal/app"
+)
+
+type versionItem struct {
+	version app.AvailableVersion
+}
+
+func (i versionItem) Title() string {
+	var tags []string
+	if i.version.Current {
+		tags = append(tags, "current")
+	}
+	if i.version.Latest {
+		tags = append(tags, "latest")
+	}
+	if len(tags) == 0 {
+		return i.version.Version
+	}
+	return fmt.Sprintf("%s (%s)", i.version.Version, strings.Join(tags, ", "))
+}
+
+func (i versionItem) Description() string {
+	var details []string
+	if i.version.Current && !i.version.InstallDate.IsZero() {
+		details = append(details, "installed "+i.version.InstallDate.Format("2006-01-02"))
+		if i.version.LastUpdate.After(i.version.InstallDate) {
+			details = append(details, "updated "+i.version.LastUpdate.Format("2006-01-02"))
+		}
+	}
+	if i.version.Cached {
+		details = append(details, fmt.Sprintf("cached %s on %s", formatSize(i.version.Size), i.version.Downloaded.Format("2006-01-02")))
+	}
+	if i.version.Mirror {
+		details = append(details, "on mirror")
+	}
+	if i.version.Latest && !i.version.Cached && !i.version.Mirror {
+		details = append(details, "download")
+	}
+	return strings.Join(details, " • ")
+}
+
+func (i versionItem) FilterValue() string {
+	return i.version.Version
+}
+
+type VersionPicker struct {
+	list     list.Model
+	selected string
+	done     bool
+}
+
+func NewVersionPicker(versions []app.AvailableVersion) VersionPicker {
+	items := make([]list.Item, len(versions))
+	selected := 0
+	for idx, version := range versions {
+		items[idx] = versionItem{version: version}
+		if version.Current {
+			selected = idx
+		}
+	}
+
+	l := list.New(items, list.NewDefaultDelegate(), 80, 20)
+	l.Title = "Cursor Versions"
+	l.Styles.Title = lipgloss.NewStyle().Bold(true).Foreground(primaryColor)
+	l.SetStatusBarItemName("version", "versions")
+	l.Select(selected)
+
+	return VersionPicker{list: l}
+}
+
+func (p VersionPicker) Init() tea.Cmd {
+	return nil
+}
+
+func (p VersionPicker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		p.list.SetSize(msg.Width, msg.Height-1)
+		return p, nil
+
+	case tea.KeyMsg:
+		if p.list.FilterState() == list.Filtering {
+			break
+		}
+		switch msg.String() {
+		case "enter":
+			if item, ok := p.list.SelectedItem().(versionItem); ok {
+				p.selected = item.version.Version
+			}
+			p.done = true
+			return p, tea.Quit
+		case "ctrl+c", "esc", "q":
+			p.done = true
+			return p, tea.Quit
+		}
+	}
+
+	var cmd tea.Cmd
+	p.list, cmd = p.list.Update(msg)
+	return p, cmd
+}
+
+func (p VersionPicker) View() string {
+	if p.done {
+		return ""
+	}
+	return p.list.View()
+}
+
+func (p VersionPicker) Selected() string {
+	return p.selected
+}
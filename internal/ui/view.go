@@ -2,8 +2,6 @@ package ui
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
 
 	"github.com/charmbracelet/lipgloss"
 )
@@ -17,13 +15,12 @@ func (m model) View() string {
 		return styleSuccess.Render(fmt.Sprintf("✨ Cursor is already up to date (version %s)!", m.currentVersion))
 	}
 
+	if m.skipped != "" {
+		return styleWarning.Render(fmt.Sprintf("Skipped Cursor %s", m.skipped))
+	}
+
 	if m.completed {
-		if m.downloadOnly {
-			pwd, _ := os.Getwd()
-			filePath := filepath.Join(pwd, appImage)
-			return styleSuccess.Render(fmt.Sprintf("✨ Cursor downloaded successfully to %s ✨", styleFilePath.Render(filePath)))
-		}
-		return styleSuccess.Render("✨ Cursor installation completed successfully!")
+		return styleSuccess.Render(m.completionMessage())
 	}
 
 	if m.err != nil {
@@ -32,7 +29,7 @@ func (m model) View() string {
 
 	var s string
 
-	s += styleTitle.Render("Cursor Installer") + "\n\n"
+	s += styleTitle.Render(m.title) + "\n\n"
 
 	progress := fmt.Sprintf("Step %d of %d", m.currentStep+1, len(m.steps))
 	s += styleProgress.Render(progress) + "\n\n"
@@ -61,5 +58,21 @@ func (m model) View() string {
 		s += "\n"
 	}
 
+	if m.cancelling {
+		s += "\n" + styleWarning.Render("Cancelling, waiting for the current step to stop...") + "\n"
+	} else if m.releaseNotes != nil {
+		s += "\n" + m.releaseNotesView()
+	} else if m.running != nil {
+		s += "\n" + styleWarning.Render(fmt.Sprintf("⚠ %v. Replacing it now can crash open windows.", m.running)) + "\n"
+		s += styleHelp.Render("w wait until closed • c ask Cursor to close • s stage for next launch • q cancel") + "\n"
+	}
+
+	if m.showDetails && len(m.outputs) > 0 {
+		s += "\n" + m.detailsView() + "\n"
+		s += styleHelp.Render("v hide details • ↑/↓ scroll • ctrl+c cancel") + "\n"
+	} else if m.running == nil && m.releaseNotes == nil && !m.cancelling && len(m.outputs) > 0 {
+		s += "\n" + styleHelp.Render("v show details • ctrl+c cancel") + "\n"
+	}
+
 	return s
 }
@@ -4,10 +4,18 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/lutefd/cursor-installer/internal/app"
 	"github.com/lutefd/cursor-installer/internal/cli"
 )
 
+var (
+	version string
+	commit  string
+	date    string
+)
+
 func main() {
+	app.SetBuildInfo(version, commit, date)
 	if err := cli.Execute(); err != nil {
 		fmt.Println(err)
 		os.Exit(1)